@@ -14,46 +14,98 @@ import (
 	"time"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	extensionscmdcontroller "github.com/gardener/gardener/extensions/pkg/controller/cmd"
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	extensionscmdwebhook "github.com/gardener/gardener/extensions/pkg/webhook/cmd"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
 	"github.com/gardener/gardener/pkg/controllerutils"
 	glogger "github.com/gardener/gardener/pkg/logger"
+	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	"github.com/go-logr/logr"
 	"github.com/urfave/cli/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/clientcmd"
 	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	"k8s.io/component-base/featuregate"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	exampleactuator "gardener-extension-example/pkg/actuator/example"
+	admissionvalidator "gardener-extension-example/pkg/admission/validator"
 	configinstall "gardener-extension-example/pkg/apis/config/install"
+	configv1alpha1 "gardener-extension-example/pkg/apis/config/v1alpha1"
 	"gardener-extension-example/pkg/controller"
+	"gardener-extension-example/pkg/debug"
+	"gardener-extension-example/pkg/gc"
 	"gardener-extension-example/pkg/heartbeat"
 	"gardener-extension-example/pkg/mgr"
+	"gardener-extension-example/pkg/preflight"
+	"gardener-extension-example/pkg/sharding"
 )
 
 // flags stores the manager flags as provided from the command-line
 type flags struct {
 	extensionName             string
 	metricsBindAddr           string
+	metricsSecureServing      bool
+	metricsCertDir            string
+	metricsCertName           string
+	metricsKeyName            string
+	metricsAuthorization      bool
 	healthProbeBindAddr       string
 	heartbeatRenewInterval    time.Duration
 	heartbeatNamespace        string
 	leaderElection            bool
 	leaderElectionID          string
 	leaderElectionNamespace   string
+	leaseDuration             time.Duration
+	renewDeadline             time.Duration
+	retryPeriod               time.Duration
+	releaseOnCancel           bool
+	gracefulShutdownTimeout   time.Duration
+	drainTimeout              time.Duration
 	ignoreOperationAnnotation bool
+	dryRun                    bool
+	pauseAnnotation           string
+	preflight                 bool
 	maxConcurrentReconciles   int
 	reconciliationTimeout     time.Duration
 	kubeconfig                string
 	zapLogLevel               string
 	zapLogFormat              string
 	resyncInterval            time.Duration
+	fullReconcileInterval     time.Duration
+	gcInterval                time.Duration
+	gcGracePeriod             time.Duration
+	gcDryRun                  bool
+	sharding                  bool
+	shardingLeaseNamespace    string
+	shardingIdentity          string
+	shardingLeaseDuration     time.Duration
 	pprofBindAddr             string
 	clientConnQPS             float32
 	clientConnBurst           int32
+	cacheStripManagedFields   bool
+	cacheSecrets              bool
+	cacheOwnedResourcesOnly   bool
+	debugHandlers             bool
+	webhook                   bool
+	webhookServerPort         int
+	webhookServerCertDir      string
+	webhookConfigNamespace    string
+	webhookConfigServicePort  int
 
 	// The following flags are meant to be specified by the Helm chart,
 	// which gardenlet will invoke during deployment. The value of each flag
@@ -67,19 +119,158 @@ type flags struct {
 	gardenletFeatureGates map[featuregate.Feature]bool
 }
 
-// getManager creates a new [ctrl.Manager] based on the parsed [flags].
-func (f *flags) getManager(ctx context.Context) (ctrl.Manager, error) {
-	m, err := mgr.New(
+// runPreflight verifies that the seed cluster provides the CRDs, RBAC
+// permissions and lease namespaces required by the controller.
+func (f *flags) runPreflight(ctx context.Context, logger logr.Logger) error {
+	logger.Info("running preflight checks")
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load cluster config: %w", err)
+	}
+
+	c, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	leaseNamespaces := []string{f.heartbeatNamespace, f.leaderElectionNamespace}
+	if f.sharding {
+		leaseNamespaces = append(leaseNamespaces, f.shardingLeaseNamespace)
+	}
+
+	checker, err := preflight.NewControllerChecker(c, leaseNamespaces...)
+	if err != nil {
+		return err
+	}
+	checkers := []*preflight.Checker{checker}
+
+	if f.metricsAuthorization {
+		metricsChecker, err := preflight.New(c, preflight.WithPermissions(preflight.ControllerMetricsPermissions()...))
+		if err != nil {
+			return err
+		}
+		checkers = append(checkers, metricsChecker)
+	}
+
+	if f.webhook {
+		webhookChecker, err := preflight.New(c, preflight.WithPermissions(preflight.ControllerWebhookPermissions(f.extensionName)...))
+		if err != nil {
+			return err
+		}
+		checkers = append(checkers, webhookChecker)
+	}
+
+	report, err := preflight.Run(ctx, checkers...)
+	if err != nil {
+		return err
+	}
+
+	report.Log(logger)
+	if report.Failed() {
+		return fmt.Errorf("%w: see the log for details", preflight.ErrChecksFailed)
+	}
+
+	return nil
+}
+
+// getGarbageCollector creates a new [gc.Collector] based on the parsed
+// [flags] and adds it to the given [ctrl.Manager]. The collector considers the
+// objects labeled as managed by the extension, and the secrets generated by
+// the secrets manager of the actuator.
+func (f *flags) getGarbageCollector(m ctrl.Manager) (*gc.Collector, error) {
+	collector, err := gc.New(
+		m.GetClient(),
+		gc.WithExtensionType(exampleactuator.ExtensionType),
+		gc.WithSelector(labels.SelectorFromSet(labels.Set{
+			exampleactuator.LabelKeyManagedBy: exampleactuator.FieldManager,
+		})),
+		gc.WithSelector(labels.SelectorFromSet(labels.Set{
+			secretsmanager.LabelKeyManagedBy:       secretsmanager.LabelValueSecretsManager,
+			secretsmanager.LabelKeyManagerIdentity: exampleactuator.SecretsManagerIdentity,
+		})),
+		gc.WithResources(&resourcesv1alpha1.ManagedResourceList{}, &corev1.SecretList{}),
+		gc.WithInterval(f.gcInterval),
+		gc.WithGracePeriod(f.gcGracePeriod),
+		gc.WithDryRun(f.dryRun || f.gcDryRun),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Add(collector); err != nil {
+		return nil, fmt.Errorf("failed to add garbage collector to manager: %w", err)
+	}
+
+	return collector, nil
+}
+
+// getSharder creates a new [sharding.Sharder] based on the parsed [flags] and
+// adds it to the given [ctrl.Manager].
+func (f *flags) getSharder(m ctrl.Manager) (*sharding.Sharder, error) {
+	identity := f.shardingIdentity
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hostname: %w", err)
+		}
+		identity = hostname
+	}
+
+	sharder, err := sharding.New(
+		m.GetClient(),
+		sharding.WithGroup(f.extensionName),
+		sharding.WithLeaseNamespace(f.shardingLeaseNamespace),
+		sharding.WithIdentity(identity),
+		sharding.WithLeaseDuration(f.shardingLeaseDuration),
+		sharding.WithRenewInterval(f.shardingLeaseDuration/4),
+		sharding.WithReader(m.GetAPIReader()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Add(sharder); err != nil {
+		return nil, fmt.Errorf("failed to add sharder to manager: %w", err)
+	}
+
+	return sharder, nil
+}
+
+// getManager creates a new [ctrl.Manager] based on the parsed [flags]. The
+// debug handlers report the operations recorded by the given
+// [controller.OperationRecorder].
+func (f *flags) getManager(ctx context.Context, recorder *controller.OperationRecorder) (ctrl.Manager, error) {
+	hb, err := heartbeat.New(
+		heartbeat.WithExtensionName(f.extensionName),
+		heartbeat.WithLeaseNamespace(f.heartbeatNamespace),
+		heartbeat.WithRenewInterval(f.heartbeatRenewInterval),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create heartbeat controller: %w", err)
+	}
+
+	opts := []mgr.Option{
 		mgr.WithContext(ctx),
 		mgr.WithAddToScheme(clientgoscheme.AddToScheme),
 		mgr.WithAddToScheme(extensionscontroller.AddToScheme),
 		mgr.WithAddToScheme(resourcesv1alpha1.AddToScheme),
 		mgr.WithInstallScheme(configinstall.Install),
 		mgr.WithMetricsAddress(f.metricsBindAddr),
+		mgr.WithMetricsSecureServing(f.metricsSecureServing),
+		mgr.WithMetricsCertDir(f.metricsCertDir),
+		mgr.WithMetricsCertName(f.metricsCertName),
+		mgr.WithMetricsKeyName(f.metricsKeyName),
+		mgr.WithMetricsAuthorization(f.metricsAuthorization),
 		mgr.WithHealthProbeAddress(f.healthProbeBindAddr),
 		mgr.WithLeaderElection(f.leaderElection),
 		mgr.WithLeaderElectionID(f.leaderElectionID),
 		mgr.WithLeaderElectionNamespace(f.leaderElectionNamespace),
+		mgr.WithLeaseDuration(f.leaseDuration),
+		mgr.WithRenewDeadline(f.renewDeadline),
+		mgr.WithRetryPeriod(f.retryPeriod),
+		mgr.WithLeaderElectionReleaseOnCancel(f.releaseOnCancel),
+		mgr.WithGracefulShutdownTimeout(f.gracefulShutdownTimeout),
 		mgr.WithMaxConcurrentReconciles(f.maxConcurrentReconciles),
 		mgr.WithReconciliationTimeout(f.reconciliationTimeout),
 		mgr.WithHealthzCheck("healthz", healthz.Ping),
@@ -89,29 +280,174 @@ func (f *flags) getManager(ctx context.Context) (ctrl.Manager, error) {
 			QPS:   f.clientConnQPS,
 			Burst: f.clientConnBurst,
 		}),
-	)
+	}
 
-	if err != nil {
-		return nil, err
+	opts = append(opts, f.getCacheOptions()...)
+
+	// Serve the validating webhook for the extension resources, so that
+	// they are validated by the same rules as the shoots in the garden.
+	// The readiness of the webhook endpoints must not depend on the
+	// heartbeat lease, so that it is only checked without the webhook.
+	if f.webhook {
+		webhookServer := webhook.NewServer(webhook.Options{
+			Port:    f.webhookServerPort,
+			CertDir: f.webhookServerCertDir,
+		})
+		opts = append(
+			opts,
+			mgr.WithWebhookServer(webhookServer),
+			mgr.WithReadyzCheck("webhook-server", webhookServer.StartedChecker()),
+		)
+	} else {
+		opts = append(opts, mgr.WithReadyzCheck("heartbeat", hb.Checker()))
 	}
 
-	hb, err := heartbeat.New(
-		heartbeat.WithExtensionName(f.extensionName),
-		heartbeat.WithLeaseNamespace(f.heartbeatNamespace),
-		heartbeat.WithRenewInterval(f.heartbeatRenewInterval),
-	)
+	// Serve read-only debug information about the extension resources via
+	// the metrics server, which guards it with the same authorization.
+	var debugHandler *debug.Handler
+	if f.debugHandlers {
+		debugHandler, err = f.getDebugHandler(recorder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create debug handler: %w", err)
+		}
+		opts = append(opts, mgr.WithExtraMetricsHandler(debug.ExtensionsPath, debugHandler))
+	}
 
+	m, err := mgr.New(opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create heartbeat controller: %w", err)
+		return nil, err
+	}
+
+	// Periodically remove objects, which have been left behind for shoot
+	// namespaces, whose Extension or Cluster resource no longer exists.
+	if f.gcInterval > 0 {
+		if _, err := f.getGarbageCollector(m); err != nil {
+			return nil, fmt.Errorf("failed to create garbage collector: %w", err)
+		}
 	}
 
 	if err := hb.SetupWithManager(ctx, m); err != nil {
 		return nil, fmt.Errorf("failed to setup heartbeat controller: %w", err)
 	}
 
+	if debugHandler != nil {
+		if err := debugHandler.SetupWithManager(m); err != nil {
+			return nil, fmt.Errorf("failed to setup debug handler: %w", err)
+		}
+	}
+
 	return m, nil
 }
 
+// getDebugHandler returns the [debug.Handler], which lists the extension
+// resources seen by the controller along with the operations recorded by the
+// given [controller.OperationRecorder] and their decoded provider config.
+func (f *flags) getDebugHandler(recorder *controller.OperationRecorder) (*debug.Handler, error) {
+	scheme := runtime.NewScheme()
+	configinstall.Install(scheme)
+	decoder := serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder(configv1alpha1.SchemeGroupVersion)
+
+	configFunc := func(ex *extensionsv1alpha1.Extension) (any, error) {
+		if ex.Spec.ProviderConfig == nil {
+			return nil, errors.New("no provider config specified")
+		}
+
+		return runtime.Decode(decoder, ex.Spec.ProviderConfig.Raw)
+	}
+
+	return debug.New(
+		debug.WithExtensionType(exampleactuator.ExtensionType),
+		debug.WithControllerName(exampleactuator.Name),
+		debug.WithOperationRecorder(recorder),
+		debug.WithConfigFunc(configFunc),
+	)
+}
+
+// addWebhooks registers the validating webhook for the
+// [extensionsv1alpha1.Extension] resources with the given [ctrl.Manager]. The
+// webhook configuration and the certificates of the webhook server are
+// managed by the Gardener extension webhook utility package.
+func (f *flags) addWebhooks(ctx context.Context, m ctrl.Manager) error {
+	wh, err := admissionvalidator.NewExtensionValidatorWebhook(m)
+	if err != nil {
+		return fmt.Errorf("failed to create extension validator webhook: %w", err)
+	}
+
+	serverOpts := &extensionscmdwebhook.ServerOptions{
+		Mode:        string(extensionswebhook.ModeService),
+		ServicePort: f.webhookConfigServicePort,
+		Namespace:   f.webhookConfigNamespace,
+	}
+
+	generalOpts := &extensionscmdcontroller.GeneralOptions{
+		GardenerVersion: f.gardenerVersion,
+	}
+
+	addToManagerOpts := extensionscmdwebhook.NewAddToManagerOptions(
+		f.extensionName,
+		"",
+		nil,
+		generalOpts,
+		serverOpts,
+		&extensionscmdwebhook.SwitchOptions{},
+	)
+	if err := addToManagerOpts.Complete(); err != nil {
+		return err
+	}
+
+	webhookConfig := addToManagerOpts.Completed()
+	webhookConfig.Switch = extensionscmdwebhook.SwitchConfig{
+		Disabled: false,
+		WebhooksFactory: func(m manager.Manager) ([]*extensionswebhook.Webhook, error) {
+			return []*extensionswebhook.Webhook{wh}, nil
+		},
+	}
+
+	// The webhook is served by the controller in the seed cluster, which
+	// is both, the source and the target cluster of the webhook.
+	if _, err := webhookConfig.AddToManager(ctx, m, nil); err != nil {
+		return fmt.Errorf("failed to setup extension webhook with manager: %w", err)
+	}
+
+	return nil
+}
+
+// getCacheOptions returns the [mgr.Option] items, which reduce the memory
+// used by the cache of the manager.
+func (f *flags) getCacheOptions() []mgr.Option {
+	opts := make([]mgr.Option, 0)
+
+	// The managed fields of the managed resources are not stripped, since
+	// they are migrated from the legacy field managers before applying.
+	if f.cacheStripManagedFields {
+		opts = append(
+			opts,
+			mgr.WithCacheTransform(&extensionsv1alpha1.Extension{}, mgr.StripManagedFields),
+			mgr.WithCacheTransform(&extensionsv1alpha1.Cluster{}, mgr.StripManagedFields),
+		)
+	}
+
+	// Secrets usually make up most of the objects in a seed cluster, but
+	// without caching them, the checksums of the referenced secrets are
+	// computed from the API server on each reconciliation. When they are
+	// not cached, the referenced secrets are watched via a metadata-only
+	// informer.
+	if !f.cacheSecrets {
+		opts = append(opts, mgr.WithUncachedObjects(&corev1.Secret{}))
+	}
+
+	// Selecting the objects by the labels of their namespace is not
+	// supported, so only the managed resources, which are labelled by the
+	// actuator, are cached. Legacy managed resources, which have not been
+	// applied yet, are not found then.
+	if f.cacheOwnedResourcesOnly {
+		selector := labels.SelectorFromSet(labels.Set{exampleactuator.LabelKeyManagedBy: exampleactuator.FieldManager})
+		opts = append(opts, mgr.WithCacheLabelSelector(&resourcesv1alpha1.ManagedResource{}, selector))
+	}
+
+	return opts
+}
+
 // flagsKey is the key used to store the parsed command-line flags in a
 // [context.Context].
 type flagsKey struct{}
@@ -151,6 +487,40 @@ func New() *cli.Command {
 				Sources:     cli.EnvVars("METRICS_BIND_ADDRESS"),
 				Destination: &flags.metricsBindAddr,
 			},
+			&cli.BoolFlag{
+				Name:        "metrics-secure-serving",
+				Usage:       "serve metrics via https",
+				Value:       false,
+				Sources:     cli.EnvVars("METRICS_SECURE_SERVING"),
+				Destination: &flags.metricsSecureServing,
+			},
+			&cli.StringFlag{
+				Name:        "metrics-cert-dir",
+				Usage:       "path to directory, which contains the metrics server key and cert, a self-signed cert is used if not set",
+				Sources:     cli.EnvVars("METRICS_CERT_DIR"),
+				Destination: &flags.metricsCertDir,
+			},
+			&cli.StringFlag{
+				Name:        "metrics-cert-name",
+				Value:       "tls.crt",
+				Usage:       "the metrics server certificate file name",
+				Sources:     cli.EnvVars("METRICS_CERT_NAME"),
+				Destination: &flags.metricsCertName,
+			},
+			&cli.StringFlag{
+				Name:        "metrics-key-name",
+				Value:       "tls.key",
+				Usage:       "the metrics server certificate key file name",
+				Sources:     cli.EnvVars("METRICS_KEY_NAME"),
+				Destination: &flags.metricsKeyName,
+			},
+			&cli.BoolFlag{
+				Name:        "metrics-authorization",
+				Usage:       "authenticate and authorize metrics requests via TokenReview and SubjectAccessReview, requires --metrics-secure-serving",
+				Value:       false,
+				Sources:     cli.EnvVars("METRICS_AUTHORIZATION"),
+				Destination: &flags.metricsAuthorization,
+			},
 			&cli.StringFlag{
 				Name:        "pprof-bind-address",
 				Usage:       "the address at which pprof binds to",
@@ -199,6 +569,48 @@ func New() *cli.Command {
 				Sources:     cli.EnvVars("LEADER_ELECTION_NAMESPACE"),
 				Destination: &flags.leaderElectionNamespace,
 			},
+			&cli.DurationFlag{
+				Name:        "leader-election-lease-duration",
+				Usage:       "duration non-leader candidates wait before acquiring the leadership",
+				Value:       15 * time.Second,
+				Sources:     cli.EnvVars("LEADER_ELECTION_LEASE_DURATION"),
+				Destination: &flags.leaseDuration,
+			},
+			&cli.DurationFlag{
+				Name:        "leader-election-renew-deadline",
+				Usage:       "duration the leader retries to renew the lease before giving up the leadership",
+				Value:       10 * time.Second,
+				Sources:     cli.EnvVars("LEADER_ELECTION_RENEW_DEADLINE"),
+				Destination: &flags.renewDeadline,
+			},
+			&cli.DurationFlag{
+				Name:        "leader-election-retry-period",
+				Usage:       "duration to wait between attempts to acquire or renew the lease",
+				Value:       2 * time.Second,
+				Sources:     cli.EnvVars("LEADER_ELECTION_RETRY_PERIOD"),
+				Destination: &flags.retryPeriod,
+			},
+			&cli.BoolFlag{
+				Name:        "leader-election-release-on-cancel",
+				Usage:       "release the leader election lease on shutdown, once all runnables have stopped",
+				Value:       true,
+				Sources:     cli.EnvVars("LEADER_ELECTION_RELEASE_ON_CANCEL"),
+				Destination: &flags.releaseOnCancel,
+			},
+			&cli.DurationFlag{
+				Name:        "graceful-shutdown-timeout",
+				Usage:       "duration to wait for runnables to stop on shutdown, negative values wait forever",
+				Value:       30 * time.Second,
+				Sources:     cli.EnvVars("GRACEFUL_SHUTDOWN_TIMEOUT"),
+				Destination: &flags.gracefulShutdownTimeout,
+			},
+			&cli.DurationFlag{
+				Name:        "drain-timeout",
+				Usage:       "duration in-flight reconciliations may continue on shutdown, should be lower than the graceful shutdown timeout",
+				Value:       controller.DefaultDrainTimeout,
+				Sources:     cli.EnvVars("DRAIN_TIMEOUT"),
+				Destination: &flags.drainTimeout,
+			},
 			&cli.BoolFlag{
 				Name:        "ignore-operation-annotation",
 				Usage:       "specifies whether to ignore operation annotation",
@@ -206,6 +618,27 @@ func New() *cli.Command {
 				Sources:     cli.EnvVars("IGNORE_OPERATION_ANNOTATION"),
 				Destination: &flags.ignoreOperationAnnotation,
 			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "report planned changes instead of applying them",
+				Value:       false,
+				Sources:     cli.EnvVars("DRY_RUN"),
+				Destination: &flags.dryRun,
+			},
+			&cli.StringFlag{
+				Name:        "pause-annotation",
+				Usage:       "annotation, which pauses the reconciliation of single extension resources when set to true (empty disables pausing)",
+				Value:       controller.DefaultPauseAnnotation,
+				Sources:     cli.EnvVars("PAUSE_ANNOTATION"),
+				Destination: &flags.pauseAnnotation,
+			},
+			&cli.BoolFlag{
+				Name:        "preflight",
+				Usage:       "check CRDs, RBAC and lease namespaces before starting the manager",
+				Value:       false,
+				Sources:     cli.EnvVars("PREFLIGHT"),
+				Destination: &flags.preflight,
+			},
 			&cli.IntFlag{
 				Name:        "max-concurrent-reconciles",
 				Usage:       "max number of concurrent reconciliations",
@@ -215,7 +648,7 @@ func New() *cli.Command {
 			},
 			&cli.DurationFlag{
 				Name:        "reconciliation-timeout",
-				Usage:       "reconcile timeout duration",
+				Usage:       "reconcile timeout duration (0 falls back to the default)",
 				Value:       controllerutils.DefaultReconciliationTimeout,
 				Sources:     cli.EnvVars("RECONCILIATION_TIMEOUT"),
 				Destination: &flags.reconciliationTimeout,
@@ -262,6 +695,129 @@ func New() *cli.Command {
 				Sources:     cli.EnvVars("RESYNC_INTERVAL"),
 				Destination: &flags.resyncInterval,
 			},
+			&cli.DurationFlag{
+				Name:        "full-reconcile-interval",
+				Usage:       "interval after which a full reconciliation is performed, even if nothing changed (0 disables skipping)",
+				Value:       exampleactuator.DefaultFullReconcileInterval,
+				Sources:     cli.EnvVars("FULL_RECONCILE_INTERVAL"),
+				Destination: &flags.fullReconcileInterval,
+			},
+			&cli.DurationFlag{
+				Name:        "gc-interval",
+				Usage:       "interval on which orphaned objects are garbage collected (0 disables the garbage collector)",
+				Value:       gc.DefaultInterval,
+				Sources:     cli.EnvVars("GC_INTERVAL"),
+				Destination: &flags.gcInterval,
+			},
+			&cli.DurationFlag{
+				Name:        "gc-grace-period",
+				Usage:       "duration for which objects must be orphaned before they are garbage collected",
+				Value:       gc.DefaultGracePeriod,
+				Sources:     cli.EnvVars("GC_GRACE_PERIOD"),
+				Destination: &flags.gcGracePeriod,
+			},
+			&cli.BoolFlag{
+				Name:        "gc-dry-run",
+				Usage:       "report orphaned objects instead of garbage collecting them",
+				Value:       false,
+				Sources:     cli.EnvVars("GC_DRY_RUN"),
+				Destination: &flags.gcDryRun,
+			},
+			&cli.BoolFlag{
+				Name:        "sharding",
+				Usage:       "share the reconciliation of the shoot namespaces across all replicas instead of electing a leader",
+				Value:       false,
+				Sources:     cli.EnvVars("SHARDING"),
+				Destination: &flags.sharding,
+			},
+			&cli.StringFlag{
+				Name:        "sharding-lease-namespace",
+				Usage:       "namespace of the leases used for coordinating the replicas in sharding mode",
+				Sources:     cli.EnvVars("SHARDING_LEASE_NAMESPACE"),
+				Destination: &flags.shardingLeaseNamespace,
+			},
+			&cli.StringFlag{
+				Name:        "sharding-identity",
+				Usage:       "identity of the replica in sharding mode (defaults to the hostname)",
+				Sources:     cli.EnvVars("POD_NAME"),
+				Destination: &flags.shardingIdentity,
+			},
+			&cli.DurationFlag{
+				Name:        "sharding-lease-duration",
+				Usage:       "duration after which a replica, which did not renew its lease, is considered gone",
+				Value:       sharding.DefaultLeaseDuration,
+				Sources:     cli.EnvVars("SHARDING_LEASE_DURATION"),
+				Destination: &flags.shardingLeaseDuration,
+			},
+			&cli.BoolFlag{
+				Name:        "cache-strip-managed-fields",
+				Usage:       "remove managed fields and last applied configuration from cached extension and cluster resources",
+				Value:       true,
+				Sources:     cli.EnvVars("CACHE_STRIP_MANAGED_FIELDS"),
+				Destination: &flags.cacheStripManagedFields,
+			},
+			&cli.BoolFlag{
+				Name:        "cache-secrets",
+				Usage:       "cache secrets instead of reading them from the API server and watching their metadata only",
+				Value:       true,
+				Sources:     cli.EnvVars("CACHE_SECRETS"),
+				Destination: &flags.cacheSecrets,
+			},
+			&cli.BoolFlag{
+				Name:        "cache-owned-resources-only",
+				Usage:       "cache only the managed resources labelled as managed by the extension",
+				Value:       false,
+				Sources:     cli.EnvVars("CACHE_OWNED_RESOURCES_ONLY"),
+				Destination: &flags.cacheOwnedResourcesOnly,
+			},
+			&cli.BoolFlag{
+				Name:        "debug-handlers",
+				Usage:       "serve read-only debug information about the extension resources via the metrics server, requires metrics authorization",
+				Value:       false,
+				Sources:     cli.EnvVars("DEBUG_HANDLERS"),
+				Destination: &flags.debugHandlers,
+			},
+			&cli.BoolFlag{
+				Name:        "webhook",
+				Usage:       "serve a validating webhook for the extension resources in the seed cluster",
+				Value:       false,
+				Sources:     cli.EnvVars("WEBHOOK"),
+				Destination: &flags.webhook,
+			},
+			&cli.IntFlag{
+				Name:        "webhook-server-port",
+				Value:       9443,
+				Usage:       "port on which the webhook server listens on",
+				Sources:     cli.EnvVars("WEBHOOK_SERVER_PORT"),
+				Destination: &flags.webhookServerPort,
+			},
+			&cli.StringFlag{
+				Name:        "webhook-server-cert-dir",
+				Usage:       "path to directory, in which the certificates of the webhook server are written",
+				Sources:     cli.EnvVars("WEBHOOK_SERVER_CERT_DIR"),
+				Destination: &flags.webhookServerCertDir,
+			},
+			&cli.StringFlag{
+				Name:        "webhook-config-namespace",
+				Value:       "garden",
+				Usage:       "namespace where the webhook CA bundle, services, etc. are created",
+				Sources:     cli.EnvVars("WEBHOOK_CONFIG_NAMESPACE"),
+				Destination: &flags.webhookConfigNamespace,
+			},
+			&cli.IntFlag{
+				Name:    "webhook-config-service-port",
+				Value:   443,
+				Usage:   "service port for the webhook of the extension resources",
+				Sources: cli.EnvVars("WEBHOOK_CONFIG_SERVICE_PORT"),
+				Validator: func(val int) error {
+					if val <= 0 {
+						return errors.New("port cannot be negative")
+					}
+
+					return nil
+				},
+				Destination: &flags.webhookConfigServicePort,
+			},
 			&cli.Float32Flag{
 				Name:        "client-conn-qps",
 				Usage:       "allowed client queries per second for the connection",
@@ -306,6 +862,26 @@ func New() *cli.Command {
 		},
 		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
 			ctrllog.SetLogger(glogger.MustNewZapLogger(flags.zapLogLevel, flags.zapLogFormat))
+
+			// The debug handlers expose the provider configs of all
+			// extension resources, so they must not be served without
+			// authorization.
+			if flags.debugHandlers && !flags.metricsAuthorization {
+				return ctx, errors.New("debug handlers require metrics authorization")
+			}
+
+			// Deletions in dry-run mode release the finalizer without
+			// deleting anything, so that the objects left behind must
+			// be removed by the garbage collector later on.
+			if flags.dryRun && flags.gcInterval == 0 {
+				return ctx, errors.New("dry-run mode requires the garbage collector")
+			}
+
+			// The same applies to deletions of paused extension
+			// resources.
+			if flags.pauseAnnotation != "" && flags.gcInterval == 0 {
+				return ctx, errors.New("pausing requires the garbage collector")
+			}
 			newCtx := context.WithValue(ctx, flagsKey{}, &flags)
 
 			return newCtx, nil
@@ -316,13 +892,34 @@ func New() *cli.Command {
 	return cmd
 }
 
+// secretWatchObject returns the object used for watching the referenced
+// secrets, which is a [metav1.PartialObjectMetadata] for a metadata-only
+// informer, unless the secrets are cached.
+func (f *flags) secretWatchObject() client.Object {
+	if f.cacheSecrets {
+		return &corev1.Secret{}
+	}
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
+	return obj
+}
+
 // runManager starts the controller manager
 func runManager(ctx context.Context, cmd *cli.Command) error {
 	logger := ctrllog.Log.WithName("manager-setup")
 	logger.Info("creating manager")
 
 	flags := getFlags(ctx)
-	m, err := flags.getManager(ctx)
+	if flags.preflight {
+		if err := flags.runPreflight(ctx, logger); err != nil {
+			return err
+		}
+	}
+
+	recorder := controller.NewOperationRecorder()
+	m, err := flags.getManager(ctx, recorder)
 	if err != nil {
 		return err
 	}
@@ -334,13 +931,43 @@ func runManager(ctx context.Context, cmd *cli.Command) error {
 		exampleactuator.WithDecoder(decoder),
 		exampleactuator.WithGardenerVersion(flags.gardenerVersion),
 		exampleactuator.WithGardenletFeatures(flags.gardenletFeatureGates),
+		exampleactuator.WithDryRun(flags.dryRun),
+		exampleactuator.WithFullReconcileInterval(flags.fullReconcileInterval),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create actuator: %w", err)
 	}
 
+	// Watch objects related to the extension resources, so that changes to
+	// them trigger a reconciliation without waiting for the next resync.
+	watchOpts := []controller.Option{
+		controller.WithWatch(
+			&resourcesv1alpha1.ManagedResource{},
+			controller.ExtensionsInNamespaceMapper(act.ExtensionType()),
+			controller.HasNamePrefix(exampleactuator.ManagedResourceNamePrefix),
+			controller.IgnoreStatusUpdates(),
+		),
+		controller.WithWatch(
+			flags.secretWatchObject(),
+			controller.ExtensionsInNamespaceMapper(act.ExtensionType()),
+			controller.HasNamePrefix(v1beta1constants.ReferencedResourcesPrefix),
+			controller.IgnoreStatusUpdates(),
+		),
+	}
+
+	// The Cluster resources are already watched, when the operation
+	// annotation is ignored.
+	if !flags.ignoreOperationAnnotation {
+		clusterWatch := controller.WithWatch(
+			&extensionsv1alpha1.Cluster{},
+			controller.ClusterToExtensionsMapper(act.ExtensionType()),
+			controller.IgnoreStatusUpdates(),
+		)
+		watchOpts = append(watchOpts, clusterWatch)
+	}
+
 	logger.Info("creating controllers")
-	c, err := controller.New(
+	controllerOpts := []controller.Option{
 		controller.WithActuator(act),
 		controller.WithName(act.Name()),
 		controller.WithExtensionType(act.ExtensionType()),
@@ -350,7 +977,26 @@ func runManager(ctx context.Context, cmd *cli.Command) error {
 		controller.WithResyncInterval(flags.resyncInterval),
 		controller.WithMaxConcurrentReconciles(flags.maxConcurrentReconciles),
 		controller.WithReconciliationTimeout(flags.reconciliationTimeout),
-	)
+		controller.WithDrainTimeout(flags.drainTimeout),
+		controller.WithPauseAnnotation(flags.pauseAnnotation),
+		controller.WithTriggerPredicate(controller.AnnotationAdded(exampleactuator.AnnotationOperation)),
+	}
+
+	if flags.debugHandlers {
+		controllerOpts = append(controllerOpts, controller.WithOperationRecorder(recorder))
+	}
+
+	// Share the reconciliation of the shoot namespaces across all replicas
+	// instead of reconciling them by the leader only.
+	if flags.sharding {
+		sharder, err := flags.getSharder(m)
+		if err != nil {
+			return fmt.Errorf("failed to create sharder: %w", err)
+		}
+		controllerOpts = append(controllerOpts, controller.WithSharder(sharder))
+	}
+
+	c, err := controller.New(append(controllerOpts, watchOpts...)...)
 	if err != nil {
 		return fmt.Errorf("failed to create a controller: %w", err)
 	}
@@ -359,6 +1005,13 @@ func runManager(ctx context.Context, cmd *cli.Command) error {
 		return fmt.Errorf("failed to setup controller with manager: %w", err)
 	}
 
+	if flags.webhook {
+		logger.Info("creating webhooks")
+		if err := flags.addWebhooks(ctx, m); err != nil {
+			return err
+		}
+	}
+
 	if flags.gardenerVersion != "" {
 		logger.Info("configured gardener version", "version", flags.gardenerVersion)
 	}
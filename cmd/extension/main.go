@@ -12,6 +12,9 @@ import (
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
 	controllercmd "gardener-extension-example/cmd/extension/controller"
+	preflightcmd "gardener-extension-example/cmd/extension/preflight"
+	rendercmd "gardener-extension-example/cmd/extension/render"
+	validatecmd "gardener-extension-example/cmd/extension/validate"
 	webhookcmd "gardener-extension-example/cmd/extension/webhook"
 	"gardener-extension-example/pkg/version"
 )
@@ -25,6 +28,9 @@ func main() {
 		Commands: []*cli.Command{
 			controllercmd.New(),
 			webhookcmd.New(),
+			validatecmd.New(),
+			rendercmd.New(),
+			preflightcmd.New(),
 		},
 	}
 
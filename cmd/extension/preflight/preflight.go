@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	glogger "github.com/gardener/gardener/pkg/logger"
+	"github.com/urfave/cli/v3"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"gardener-extension-example/pkg/preflight"
+)
+
+// flags stores the preflight flags as provided from the command-line
+type flags struct {
+	kubeconfig              string
+	heartbeatNamespace      string
+	leaderElectionNamespace string
+}
+
+// New creates a new [cli.Command] for checking the requirements of the
+// extension controller.
+func New() *cli.Command {
+	flags := flags{}
+
+	cmd := &cli.Command{
+		Name:  "preflight",
+		Usage: "check CRDs, RBAC and lease namespaces required by the controller",
+		Description: "Verifies that the seed cluster serves the required CRDs, that the\n" +
+			"controller is granted all permissions it needs and that the namespaces of\n" +
+			"its leases exist. The flags and environment variables are the same as for\n" +
+			"the controller command, so that the checks can be run with the identity\n" +
+			"and configuration of the controller.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "kubeconfig",
+				Usage:       "path to a kubeconfig when running out-of-cluster",
+				Sources:     cli.EnvVars("KUBECONFIG"),
+				Destination: &flags.kubeconfig,
+				Action: func(ctx context.Context, c *cli.Command, val string) error {
+					return os.Setenv(clientcmd.RecommendedConfigPathEnvVar, val)
+				},
+			},
+			&cli.StringFlag{
+				Name:        "heartbeat-namespace",
+				Usage:       "namespace to use for the heartbeat lease",
+				Value:       "gardener-extension-example",
+				Sources:     cli.EnvVars("HEARTBEAT_NAMESPACE"),
+				Destination: &flags.heartbeatNamespace,
+			},
+			&cli.StringFlag{
+				Name:        "leader-election-namespace",
+				Usage:       "namespace to use for the leader election lease",
+				Value:       "gardener-extension-example",
+				Sources:     cli.EnvVars("LEADER_ELECTION_NAMESPACE"),
+				Destination: &flags.leaderElectionNamespace,
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			ctrllog.SetLogger(glogger.MustNewZapLogger(glogger.InfoLevel, glogger.FormatText))
+
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return runPreflight(ctx, cmd, flags)
+		},
+	}
+
+	return cmd
+}
+
+// runPreflight runs the preflight checks for the controller and prints the
+// report. A non-zero exit code is returned when checks fail.
+func runPreflight(ctx context.Context, cmd *cli.Command, flags flags) error {
+	restConfig, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load cluster config: %w", err)
+	}
+
+	c, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	checker, err := preflight.NewControllerChecker(c, flags.heartbeatNamespace, flags.leaderElectionNamespace)
+	if err != nil {
+		return err
+	}
+
+	report, err := checker.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := report.Write(cmd.Root().Writer); err != nil {
+		return err
+	}
+
+	if report.Failed() {
+		return cli.Exit(preflight.ErrChecksFailed.Error(), 1)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package render
+
+import (
+	"context"
+	"errors"
+
+	glogger "github.com/gardener/gardener/pkg/logger"
+	"github.com/urfave/cli/v3"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"gardener-extension-example/pkg/actuator/example"
+	"gardener-extension-example/pkg/render"
+)
+
+// flags stores the render flags as provided from the command-line
+type flags struct {
+	image   string
+	verbose bool
+}
+
+// New creates a new [cli.Command] for rendering the objects deployed by the
+// actuator.
+func New() *cli.Command {
+	flags := flags{}
+
+	cmd := &cli.Command{
+		Name:      "render",
+		Aliases:   []string{"r"},
+		Usage:     "render the objects, which the actuator deploys",
+		ArgsUsage: "FILE [FILE...]",
+		Description: "Runs the actuator against an in-memory fake client and prints the resulting\n" +
+			"ManagedResources along with their decoded objects as YAML. The given files\n" +
+			"must provide an Extension resource or a Shoot, which enables the extension,\n" +
+			"and may provide a Cluster resource and the secrets referenced by the Shoot.\n" +
+			"Use \"-\" to read from the standard input.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "image",
+				Usage:       "image of the seed-side workload",
+				Value:       example.DefaultImage,
+				Destination: &flags.image,
+			},
+			&cli.BoolFlag{
+				Name:        "verbose",
+				Usage:       "print the logs of the actuator",
+				Value:       false,
+				Destination: &flags.verbose,
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			ctrllog.SetLogger(glogger.MustNewZapLogger(glogger.InfoLevel, glogger.FormatText))
+
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return runRender(ctx, cmd, flags)
+		},
+	}
+
+	return cmd
+}
+
+// runRender renders the objects for the input from the files given as
+// arguments.
+func runRender(ctx context.Context, cmd *cli.Command, flags flags) error {
+	if cmd.NArg() == 0 {
+		return errors.New("no files specified")
+	}
+
+	opts := []render.Option{
+		render.WithImage(flags.image),
+	}
+	if flags.verbose {
+		opts = append(opts, render.WithLogger(ctrllog.Log.WithName("render")))
+	}
+
+	renderer, err := render.New(opts...)
+	if err != nil {
+		return err
+	}
+
+	in, err := renderer.LoadFiles(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Render(ctx, in, cmd.Root().Writer)
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	glogger "github.com/gardener/gardener/pkg/logger"
+	"github.com/urfave/cli/v3"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"gardener-extension-example/pkg/manifest"
+)
+
+// New creates a new [cli.Command] for validating manifests.
+func New() *cli.Command {
+	cmd := &cli.Command{
+		Name:      "validate",
+		Aliases:   []string{"v"},
+		Usage:     "validate extension config in ExampleConfig and Shoot manifests",
+		ArgsUsage: "FILE [FILE...]",
+		Description: "Validates the extension configuration in the given YAML files with the same\n" +
+			"strict decoding and validation as the admission webhook. Files may contain\n" +
+			"bare ExampleConfig documents or complete Shoot manifests. Use \"-\" to read\n" +
+			"from the standard input.",
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			ctrllog.SetLogger(glogger.MustNewZapLogger(glogger.InfoLevel, glogger.FormatText))
+
+			return ctx, nil
+		},
+		Action: runValidate,
+	}
+
+	return cmd
+}
+
+// runValidate validates the manifests in the files given as arguments and
+// reports any problems found. A non-zero exit code is returned when problems
+// are found.
+func runValidate(ctx context.Context, cmd *cli.Command) error {
+	if cmd.NArg() == 0 {
+		return errors.New("no files specified")
+	}
+
+	validator, err := manifest.New()
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, path := range cmd.Args().Slice() {
+		problems, err := validator.ValidateFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, problem := range problems {
+			fmt.Fprintln(cmd.Root().Writer, problem)
+		}
+		total += len(problems)
+	}
+
+	if total > 0 {
+		return cli.Exit(fmt.Sprintf("%d problem(s) found", total), 1)
+	}
+
+	return nil
+}
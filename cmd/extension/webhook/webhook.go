@@ -27,6 +27,7 @@ import (
 	componentbaseconfigv1alpha1 "k8s.io/component-base/config/v1alpha1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/cluster"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -36,17 +37,29 @@ import (
 
 	admissionvalidator "gardener-extension-example/pkg/admission/validator"
 	configinstall "gardener-extension-example/pkg/apis/config/install"
+	"gardener-extension-example/pkg/heartbeat"
 	"gardener-extension-example/pkg/mgr"
+	"gardener-extension-example/pkg/preflight"
 )
 
 // flags stores the webhook flags as provided from the command-line
 type flags struct {
 	extensionName               string
 	metricsBindAddr             string
+	metricsSecureServing        bool
+	metricsCertDir              string
+	metricsCertName             string
+	metricsKeyName              string
+	metricsAuthorization        bool
 	healthProbeBindAddr         string
 	leaderElection              bool
 	leaderElectionID            string
 	leaderElectionNamespace     string
+	leaseDuration               time.Duration
+	renewDeadline               time.Duration
+	retryPeriod                 time.Duration
+	releaseOnCancel             bool
+	gracefulShutdownTimeout     time.Duration
 	kubeconfig                  string
 	gardenKubeconfig            string
 	zapLogLevel                 string
@@ -69,6 +82,9 @@ type flags struct {
 	sourceCluster               cluster.Cluster
 	maxConcurrentReconciles     int
 	reconciliationTimeout       time.Duration
+	preflight                   bool
+	heartbeatRenewInterval      time.Duration
+	heartbeatNamespace          string
 }
 
 // getLogger returns a [logr.Logger] based on the specified command-line
@@ -77,6 +93,73 @@ func (f *flags) getLogger() logr.Logger {
 	return glogger.MustNewZapLogger(f.zapLogLevel, f.zapLogFormat)
 }
 
+// runPreflight verifies that the garden cluster provides the CRDs and RBAC
+// permissions required by the webhook, and that the webhook is granted the
+// permissions for its certificates and leases in the runtime cluster.
+func (f *flags) runPreflight(ctx context.Context, logger logr.Logger) error {
+	logger.Info("running preflight checks")
+	gardenConfig, err := clientcmd.BuildConfigFromFlags("", f.gardenKubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to load garden cluster config: %w", err)
+	}
+
+	gardenClient, err := client.New(gardenConfig, client.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create garden cluster client: %w", err)
+	}
+
+	gardenChecker, err := preflight.New(
+		gardenClient,
+		preflight.WithCRDs(preflight.WebhookCRDs()...),
+		preflight.WithPermissions(preflight.WebhookPermissions(f.extensionName)...),
+	)
+	if err != nil {
+		return err
+	}
+
+	runtimeConfig, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load source cluster config: %w", err)
+	}
+
+	runtimeClient, err := client.New(runtimeConfig, client.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to create source cluster client: %w", err)
+	}
+
+	runtimeOpts := []preflight.Option{
+		preflight.WithNamespaces(f.webhookConfigNamespace, f.heartbeatNamespace),
+		preflight.WithPermissions(preflight.WebhookRuntimePermissions(f.webhookConfigNamespace, f.leaderElectionID, f.heartbeatLeaseName())...),
+	}
+	if f.metricsAuthorization {
+		runtimeOpts = append(runtimeOpts, preflight.WithPermissions(preflight.WebhookMetricsPermissions()...))
+	}
+
+	runtimeChecker, err := preflight.New(runtimeClient, runtimeOpts...)
+	if err != nil {
+		return err
+	}
+
+	report, err := preflight.Run(ctx, gardenChecker, runtimeChecker)
+	if err != nil {
+		return err
+	}
+
+	report.Log(logger)
+	if report.Failed() {
+		return fmt.Errorf("%w: see the log for details", preflight.ErrChecksFailed)
+	}
+
+	return nil
+}
+
+// heartbeatLeaseName returns the name of the heartbeat lease of the webhook,
+// which differs from the one of the controller, so that both can be deployed
+// to the same namespace.
+func (f *flags) heartbeatLeaseName() string {
+	return f.extensionName + "-heartbeat"
+}
+
 // getManager creates a new [ctrl.Manager] based on the parsed [flags].
 func (f *flags) getManager(ctx context.Context) (ctrl.Manager, error) {
 	logger := f.getLogger()
@@ -121,6 +204,20 @@ func (f *flags) getManager(ctx context.Context) (ctrl.Manager, error) {
 	// package.
 	f.sourceCluster = sourceCluster
 
+	// The heartbeat lease is maintained in the source cluster, which is
+	// where the webhook is running.
+	hb, err := heartbeat.New(
+		heartbeat.WithExtensionName(f.extensionName),
+		heartbeat.WithLeaseNamespace(f.heartbeatNamespace),
+		heartbeat.WithLeaseName(f.heartbeatLeaseName()),
+		heartbeat.WithRenewInterval(f.heartbeatRenewInterval),
+		heartbeat.WithClient(sourceCluster.GetClient()),
+		heartbeat.WithReader(sourceCluster.GetAPIReader()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create heartbeat controller: %w", err)
+	}
+
 	targetClusterConfig, err := clientcmd.BuildConfigFromFlags("", f.gardenKubeconfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load garden cluster config: %w", err)
@@ -134,10 +231,21 @@ func (f *flags) getManager(ctx context.Context) (ctrl.Manager, error) {
 		mgr.WithInstallScheme(gardencoreinstall.Install),
 		mgr.WithInstallScheme(configinstall.Install),
 		mgr.WithMetricsAddress(f.metricsBindAddr),
+		mgr.WithMetricsSecureServing(f.metricsSecureServing),
+		mgr.WithMetricsCertDir(f.metricsCertDir),
+		mgr.WithMetricsCertName(f.metricsCertName),
+		mgr.WithMetricsKeyName(f.metricsKeyName),
+		mgr.WithMetricsAuthorization(f.metricsAuthorization),
+		mgr.WithMetricsAuthorizationConfig(sourceClusterConfig),
 		mgr.WithHealthProbeAddress(f.healthProbeBindAddr),
 		mgr.WithLeaderElection(f.leaderElection),
 		mgr.WithLeaderElectionID(f.leaderElectionID),
 		mgr.WithLeaderElectionNamespace(f.leaderElectionNamespace),
+		mgr.WithLeaseDuration(f.leaseDuration),
+		mgr.WithRenewDeadline(f.renewDeadline),
+		mgr.WithRetryPeriod(f.retryPeriod),
+		mgr.WithLeaderElectionReleaseOnCancel(f.releaseOnCancel),
+		mgr.WithGracefulShutdownTimeout(f.gracefulShutdownTimeout),
 		mgr.WithLeaderElectionConfig(sourceClusterConfig),
 		mgr.WithMaxConcurrentReconciles(f.maxConcurrentReconciles),
 		mgr.WithReconciliationTimeout(f.reconciliationTimeout),
@@ -163,6 +271,10 @@ func (f *flags) getManager(ctx context.Context) (ctrl.Manager, error) {
 		return nil, fmt.Errorf("failed to setup ready check: %w", err)
 	}
 
+	if err := hb.SetupWithManager(ctx, m); err != nil {
+		return nil, fmt.Errorf("failed to setup heartbeat controller: %w", err)
+	}
+
 	return m, nil
 }
 
@@ -231,6 +343,40 @@ func New() *cli.Command {
 				Sources:     cli.EnvVars("METRICS_BIND_ADDRESS"),
 				Destination: &flags.metricsBindAddr,
 			},
+			&cli.BoolFlag{
+				Name:        "metrics-secure-serving",
+				Usage:       "serve metrics via https",
+				Value:       false,
+				Sources:     cli.EnvVars("METRICS_SECURE_SERVING"),
+				Destination: &flags.metricsSecureServing,
+			},
+			&cli.StringFlag{
+				Name:        "metrics-cert-dir",
+				Usage:       "path to directory, which contains the metrics server key and cert, a self-signed cert is used if not set",
+				Sources:     cli.EnvVars("METRICS_CERT_DIR"),
+				Destination: &flags.metricsCertDir,
+			},
+			&cli.StringFlag{
+				Name:        "metrics-cert-name",
+				Value:       "tls.crt",
+				Usage:       "the metrics server certificate file name",
+				Sources:     cli.EnvVars("METRICS_CERT_NAME"),
+				Destination: &flags.metricsCertName,
+			},
+			&cli.StringFlag{
+				Name:        "metrics-key-name",
+				Value:       "tls.key",
+				Usage:       "the metrics server certificate key file name",
+				Sources:     cli.EnvVars("METRICS_KEY_NAME"),
+				Destination: &flags.metricsKeyName,
+			},
+			&cli.BoolFlag{
+				Name:        "metrics-authorization",
+				Usage:       "authenticate and authorize metrics requests via TokenReview and SubjectAccessReview, requires --metrics-secure-serving",
+				Value:       false,
+				Sources:     cli.EnvVars("METRICS_AUTHORIZATION"),
+				Destination: &flags.metricsAuthorization,
+			},
 			&cli.StringFlag{
 				Name:        "pprof-bind-address",
 				Usage:       "the address at which pprof binds to",
@@ -265,6 +411,62 @@ func New() *cli.Command {
 				Sources:     cli.EnvVars("LEADER_ELECTION_NAMESPACE"),
 				Destination: &flags.leaderElectionNamespace,
 			},
+			&cli.DurationFlag{
+				Name:        "leader-election-lease-duration",
+				Usage:       "duration non-leader candidates wait before acquiring the leadership",
+				Value:       15 * time.Second,
+				Sources:     cli.EnvVars("LEADER_ELECTION_LEASE_DURATION"),
+				Destination: &flags.leaseDuration,
+			},
+			&cli.DurationFlag{
+				Name:        "leader-election-renew-deadline",
+				Usage:       "duration the leader retries to renew the lease before giving up the leadership",
+				Value:       10 * time.Second,
+				Sources:     cli.EnvVars("LEADER_ELECTION_RENEW_DEADLINE"),
+				Destination: &flags.renewDeadline,
+			},
+			&cli.DurationFlag{
+				Name:        "leader-election-retry-period",
+				Usage:       "duration to wait between attempts to acquire or renew the lease",
+				Value:       2 * time.Second,
+				Sources:     cli.EnvVars("LEADER_ELECTION_RETRY_PERIOD"),
+				Destination: &flags.retryPeriod,
+			},
+			&cli.BoolFlag{
+				Name:        "leader-election-release-on-cancel",
+				Usage:       "release the leader election lease on shutdown, once all runnables have stopped",
+				Value:       true,
+				Sources:     cli.EnvVars("LEADER_ELECTION_RELEASE_ON_CANCEL"),
+				Destination: &flags.releaseOnCancel,
+			},
+			&cli.DurationFlag{
+				Name:        "graceful-shutdown-timeout",
+				Usage:       "duration to wait for runnables to stop on shutdown, negative values wait forever",
+				Value:       30 * time.Second,
+				Sources:     cli.EnvVars("GRACEFUL_SHUTDOWN_TIMEOUT"),
+				Destination: &flags.gracefulShutdownTimeout,
+			},
+			&cli.BoolFlag{
+				Name:        "preflight",
+				Usage:       "check CRDs and RBAC in the garden and runtime cluster before starting the manager",
+				Value:       false,
+				Sources:     cli.EnvVars("PREFLIGHT"),
+				Destination: &flags.preflight,
+			},
+			&cli.DurationFlag{
+				Name:        "heartbeat-renew-interval",
+				Usage:       "renew heartbeat lease on specified interval",
+				Value:       30 * time.Second,
+				Sources:     cli.EnvVars("HEARTBEAT_RENEW_INTERVAL"),
+				Destination: &flags.heartbeatRenewInterval,
+			},
+			&cli.StringFlag{
+				Name:        "heartbeat-namespace",
+				Usage:       "namespace in the source cluster to use for the heartbeat lease",
+				Value:       "gardener-extension-example",
+				Sources:     cli.EnvVars("HEARTBEAT_NAMESPACE"),
+				Destination: &flags.heartbeatNamespace,
+			},
 			&cli.IntFlag{
 				Name:        "max-concurrent-reconciles",
 				Usage:       "max number of concurrent reconciliations",
@@ -456,6 +658,12 @@ func runWebhookServer(ctx context.Context, cmd *cli.Command) error {
 	logger.Info("creating manager")
 
 	flags := getFlags(ctx)
+	if flags.preflight {
+		if err := flags.runPreflight(ctx, logger); err != nil {
+			return err
+		}
+	}
+
 	m, err := flags.getManager(ctx)
 	if err != nil {
 		return err
@@ -10,15 +10,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/extension"
-	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/component-base/featuregate"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"gardener-extension-example/pkg/apis/config"
@@ -44,6 +46,13 @@ const (
 type Actuator struct {
 	client  client.Client
 	decoder runtime.Decoder
+	image   string
+	clock   clock.Clock
+	dryRun  bool
+
+	// fullReconcileInterval is the interval after which a full
+	// reconciliation is performed, even if none of its inputs changed.
+	fullReconcileInterval time.Duration
 
 	// The following fields are usually derived from the list of extra Helm
 	// values provided by gardenlet during the deployment of the extension.
@@ -69,6 +78,9 @@ func New(c client.Client, opts ...Option) (*Actuator, error) {
 
 	act := &Actuator{
 		client:                c,
+		image:                 DefaultImage,
+		clock:                 clock.RealClock{},
+		fullReconcileInterval: DefaultFullReconcileInterval,
 		gardenletFeatureGates: make(map[featuregate.Feature]bool),
 	}
 
@@ -97,6 +109,61 @@ func WithDecoder(d runtime.Decoder) Option {
 	return opt
 }
 
+// WithImage is an [Option], which configures the [Actuator] to use the given
+// image for the seed-side workload.
+func WithImage(image string) Option {
+	opt := func(a *Actuator) error {
+		a.image = image
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithClock is an [Option], which configures the [Actuator] to use the given
+// [clock.Clock].
+func WithClock(clk clock.Clock) Option {
+	opt := func(a *Actuator) error {
+		a.clock = clk
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithDryRun is an [Option], which configures the [Actuator] to run in dry-run
+// mode. In dry-run mode the [Actuator] computes the changes it would apply and
+// records them in the provider status and in the logs, without modifying any
+// resources in the seed cluster.
+func WithDryRun(dryRun bool) Option {
+	opt := func(a *Actuator) error {
+		a.dryRun = dryRun
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithFullReconcileInterval is an [Option], which configures the interval
+// after which the [Actuator] performs a full reconciliation, even if none of
+// its inputs changed since the last full reconciliation. A zero interval
+// disables skipping reconciliations.
+func WithFullReconcileInterval(interval time.Duration) Option {
+	opt := func(a *Actuator) error {
+		if interval < 0 {
+			return fmt.Errorf("%w: negative full reconcile interval", ErrInvalidActuator)
+		}
+		a.fullReconcileInterval = interval
+
+		return nil
+	}
+
+	return opt
+}
+
 // WithGardenerVersion is an [Option], which configures the [Actuator] with the
 // given version of Gardener. This version of Gardener is usually provided by
 // the gardenlet as part of the extra Helm values during deployment of the
@@ -165,36 +232,141 @@ func (a *Actuator) Reconcile(ctx context.Context, logger logr.Logger, ex *extens
 		metrics.ActuatorOperationTotal.WithLabelValues(clusterName, "reconcile").Inc()
 	}()
 
-	logger.Info("reconciling extension", "name", ex.Name, "cluster", clusterName)
+	logger = logger.WithValues("generation", ex.Generation)
+
+	return classifyError(a.reconcile(ctx, logger, ex, clusterName))
+}
 
+// reconcile reconciles the [extensionsv1alpha1.Extension] resource of the
+// cluster with the given name.
+func (a *Actuator) reconcile(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension, clusterName string) error {
 	cluster, err := extensionscontroller.GetCluster(ctx, a.client, clusterName)
 	if err != nil {
-		return fmt.Errorf("failed to get cluster: %w", err)
-	}
+		err = fmt.Errorf("failed to get cluster: %w", err)
+		if apierrors.IsNotFound(err) {
+			return missingDependency(err)
+		}
 
-	// Nothing to do here, if the shoot cluster is hibernated at the moment.
-	if v1beta1helper.HibernationIsEnabled(cluster.Shoot) {
-		return nil
+		return err
 	}
 
+	logger = withClusterValues(logger, cluster)
+	logger.Info("reconciling extension", "name", ex.Name, "cluster", clusterName)
+
 	// Parse and validate the provider config
 	if ex.Spec.ProviderConfig == nil {
-		return errors.New("no provider config specified")
+		return configurationProblem(errors.New("no provider config specified"))
 	}
 
 	// Decode provider spec configuration into our known config type.
 	var cfg config.ExampleConfig
 	if err := runtime.DecodeInto(a.decoder, ex.Spec.ProviderConfig.Raw, &cfg); err != nil {
-		return fmt.Errorf("invalid provider spec configuration: %w", err)
+		return configurationProblem(fmt.Errorf("invalid provider spec configuration: %w", err))
 	}
 
 	if err := validation.Validate(cfg); err != nil {
+		return configurationProblem(err)
+	}
+
+	if a.dryRun {
+		return a.reconcileDryRun(ctx, logger, ex, cluster, cfg)
+	}
+
+	// Drop any plan, which was recorded in dry-run mode previously
+	if err := a.clearPlan(ctx, ex); err != nil {
+		return err
+	}
+
+	// Skip the reconciliation, if none of its inputs changed since the last
+	// full reconciliation.
+	status, err := a.getStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := a.computeChecksum(ctx, ex, cluster, cfg)
+	if err != nil {
+		return err
+	}
+
+	// Custom operations requested via annotation are performed regardless
+	// of whether the inputs changed.
+	if err := a.forgetShootOperation(ctx, ex, cluster, status); err != nil {
+		return err
+	}
+
+	if op, source := requestedOperation(ex, cluster, status); op != "" {
+		return a.reconcileOperation(ctx, logger, ex, cluster, cfg, checksum, op, source)
+	}
+
+	reason, err := a.needsFullReconcile(ctx, ex, cluster, status, checksum)
+	if err != nil {
+		return err
+	}
+
+	if reason == "" {
+		logger.Info("inputs unchanged since last full reconciliation, skipping", "checksum", checksum)
+		metrics.ActuatorReconcileTotal.WithLabelValues(clusterName, "skipped").Inc()
+
+		return nil
+	}
+
+	logger.Info("performing full reconciliation", "reason", reason)
+
+	return a.fullReconcile(ctx, logger, ex, cluster, cfg, checksum)
+}
+
+// fullReconcile performs a full reconciliation of the given
+// [extensionsv1alpha1.Extension] resource and records the given checksum of its
+// inputs on success.
+func (a *Actuator) fullReconcile(
+	ctx context.Context,
+	logger logr.Logger,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	cfg config.ExampleConfig,
+	checksum string,
+) error {
+	metrics.ActuatorReconcileTotal.WithLabelValues(ex.Namespace, "full").Inc()
+
+	// Invalidate the checksum of the last full reconciliation, so that the
+	// next reconciliation is not skipped, if this one fails.
+	if err := a.recordChecksum(ctx, ex, ""); err != nil {
+		return err
+	}
+
+	// Generate the secrets for the seed-side components
+	sm, err := a.newSecretsManager(ctx, logger, a.client, cluster)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := a.generateSecrets(ctx, logger, ex, cluster, sm)
+	if err != nil {
 		return err
 	}
 
 	// TODO(user): implement the main reconciliation logic
 
-	return nil
+	// Deploy the seed-side components, scaling them down or up depending
+	// on the hibernation settings of the shoot.
+	if err := a.reconcileComponents(ctx, logger, ex, cluster, cfg, secrets); err != nil {
+		return err
+	}
+
+	// Remove the ManagedResources of components, which are no longer
+	// rendered.
+	if err := a.pruneComponents(ctx, ex.Namespace); err != nil {
+		return err
+	}
+
+	// Remove secrets, which are no longer used, e.g. old CAs after the CA
+	// rotation of the shoot has been completed.
+	if err := a.cleanupSecrets(ctx, ex, sm); err != nil {
+		return err
+	}
+
+	return a.recordChecksum(ctx, ex, checksum)
 }
 
 // Delete deletes any resources managed by the [Actuator]. This method
@@ -205,11 +377,26 @@ func (a *Actuator) Delete(ctx context.Context, logger logr.Logger, ex *extension
 		metrics.ActuatorOperationTotal.WithLabelValues(ex.Namespace, "delete").Inc()
 	}()
 
+	logger = a.withExtensionValues(ctx, logger, ex)
 	logger.Info("deleting resources managed by extension")
 
-	// TODO(user): implement logic for deleting anything managed by the extension
+	if a.dryRun {
+		return a.deleteDryRun(ctx, logger, ex, "delete")
+	}
+
+	return classifyError(a.delete(ctx, ex))
+}
+
+// delete deletes any resources managed by the [Actuator] for the given
+// [extensionsv1alpha1.Extension] resource.
+func (a *Actuator) delete(ctx context.Context, ex *extensionsv1alpha1.Extension) error {
+	// TODO(user): implement logic for deleting anything else managed by the extension
+
+	if err := a.deleteComponents(ctx, ex.Namespace); err != nil {
+		return err
+	}
 
-	return nil
+	return a.deleteSecrets(ctx, ex.Namespace)
 }
 
 // ForceDelete signals the [Actuator] to delete any resources managed by it,
@@ -221,11 +408,14 @@ func (a *Actuator) ForceDelete(ctx context.Context, logger logr.Logger, ex *exte
 		metrics.ActuatorOperationTotal.WithLabelValues(ex.Namespace, "force_delete").Inc()
 	}()
 
+	logger = a.withExtensionValues(ctx, logger, ex)
 	logger.Info("shoot has been force-deleted, deleting resources managed by extension")
 
-	// TODO(user): implement logic for deleting anything managed by the extension
+	if a.dryRun {
+		return a.deleteDryRun(ctx, logger, ex, "force-delete")
+	}
 
-	return nil
+	return classifyError(a.delete(ctx, ex))
 }
 
 // Restore restores the resources managed by the extension [Actuator]. This
@@ -236,6 +426,14 @@ func (a *Actuator) Restore(ctx context.Context, logger logr.Logger, ex *extensio
 		metrics.ActuatorOperationTotal.WithLabelValues(ex.Namespace, "restore").Inc()
 	}()
 
+	// Restore the persisted secrets before reconciling, so that they are
+	// re-used by the secrets manager. Nothing is restored in dry-run mode.
+	if !a.dryRun {
+		if err := a.restoreState(ctx, ex); err != nil {
+			return classifyError(err)
+		}
+	}
+
 	return a.Reconcile(ctx, logger, ex)
 }
 
@@ -6,23 +6,195 @@ package example_test
 
 import (
 	"encoding/json"
+	"strings"
+	"time"
 
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
 	corev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	"github.com/gardener/gardener/pkg/utils/managedresources"
+	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/component-base/featuregate"
+	testclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	exampleactuator "gardener-extension-example/pkg/actuator/example"
 	"gardener-extension-example/pkg/apis/config"
 )
 
+// markComponentsHealthy marks all ManagedResources in the given namespace as
+// applied and healthy, since there is no gardener-resource-manager running in
+// the test environment.
+func markComponentsHealthy(namespace string) {
+	var items resourcesv1alpha1.ManagedResourceList
+	Expect(k8sClient.List(ctx, &items, client.InNamespace(namespace))).To(Succeed())
+	for _, mr := range items.Items {
+		patch := client.MergeFrom(mr.DeepCopy())
+		mr.Status.ObservedGeneration = mr.Generation
+		mr.Status.Conditions = []corev1beta1.Condition{
+			{
+				Type:               resourcesv1alpha1.ResourcesApplied,
+				Status:             corev1beta1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+				LastUpdateTime:     metav1.Now(),
+			},
+			{
+				Type:               resourcesv1alpha1.ResourcesHealthy,
+				Status:             corev1beta1.ConditionTrue,
+				LastTransitionTime: metav1.Now(),
+				LastUpdateTime:     metav1.Now(),
+			},
+		}
+		Expect(k8sClient.Status().Patch(ctx, &mr, patch)).To(Succeed())
+	}
+}
+
+// getWorkload returns the seed-side workload deployed via ManagedResource in
+// the given namespace.
+func getWorkload(namespace string) *appsv1.Deployment {
+	objects, err := managedresources.GetObjects(ctx, k8sClient, namespace, exampleactuator.ManagedResourceName(exampleactuator.ComponentWorkload))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(objects).To(HaveLen(1))
+	deployment, ok := objects[0].(*appsv1.Deployment)
+	Expect(ok).To(BeTrue())
+
+	return deployment
+}
+
+// getWorkloadReplicas returns the number of replicas of the seed-side workload
+// deployed via ManagedResource in the given namespace.
+func getWorkloadReplicas(namespace string) int32 {
+	deployment := getWorkload(namespace)
+	Expect(deployment.Spec.Replicas).NotTo(BeNil())
+
+	return *deployment.Spec.Replicas
+}
+
+// listGeneratedSecrets returns the secrets with the given name generated by
+// the secrets manager of the actuator in the given namespace.
+func listGeneratedSecrets(namespace, name string) []corev1.Secret {
+	var items corev1.SecretList
+	Expect(k8sClient.List(
+		ctx,
+		&items,
+		client.InNamespace(namespace),
+		client.MatchingLabels{
+			secretsmanager.LabelKeyManagedBy:       secretsmanager.LabelValueSecretsManager,
+			secretsmanager.LabelKeyManagerIdentity: exampleactuator.SecretsManagerIdentity,
+			secretsmanager.LabelKeyName:            name,
+		},
+	)).To(Succeed())
+
+	return items.Items
+}
+
+// getState returns the decoded state of the given extension resource.
+func getState(ex *extensionsv1alpha1.Extension) config.ExampleState {
+	Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ex), ex)).To(Succeed())
+	Expect(ex.Status.State).NotTo(BeNil())
+
+	var state config.ExampleState
+	decoder := serializer.NewCodecFactory(scheme.Scheme, serializer.EnableStrict).UniversalDecoder()
+	Expect(runtime.DecodeInto(decoder, ex.Status.State.Raw, &state)).To(Succeed())
+
+	return state
+}
+
+// getHibernationPhase returns the hibernation phase from the provider status of
+// the given extension resource.
+func getHibernationPhase(ex *extensionsv1alpha1.Extension) config.HibernationPhase {
+	Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ex), ex)).To(Succeed())
+	Expect(ex.Status.ProviderStatus).NotTo(BeNil())
+
+	var status config.ExampleStatus
+	decoder := serializer.NewCodecFactory(scheme.Scheme, serializer.EnableStrict).UniversalDecoder()
+	Expect(runtime.DecodeInto(decoder, ex.Status.ProviderStatus.Raw, &status)).To(Succeed())
+
+	return status.HibernationPhase
+}
+
+// getLastFullReconcileTime returns the time of the last full reconciliation
+// from the provider status of the given extension resource.
+func getLastFullReconcileTime(ex *extensionsv1alpha1.Extension) time.Time {
+	Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ex), ex)).To(Succeed())
+	Expect(ex.Status.ProviderStatus).NotTo(BeNil())
+
+	var status config.ExampleStatus
+	decoder := serializer.NewCodecFactory(scheme.Scheme, serializer.EnableStrict).UniversalDecoder()
+	Expect(runtime.DecodeInto(decoder, ex.Status.ProviderStatus.Raw, &status)).To(Succeed())
+	Expect(status.Checksum).NotTo(BeEmpty())
+	Expect(status.LastFullReconcileTime).NotTo(BeNil())
+
+	return status.LastFullReconcileTime.Time
+}
+
+// getPlan returns the dry-run plan from the provider status of the given
+// extension resource.
+func getPlan(ex *extensionsv1alpha1.Extension) *config.Plan {
+	Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ex), ex)).To(Succeed())
+	Expect(ex.Status.ProviderStatus).NotTo(BeNil())
+
+	var status config.ExampleStatus
+	decoder := serializer.NewCodecFactory(scheme.Scheme, serializer.EnableStrict).UniversalDecoder()
+	Expect(runtime.DecodeInto(decoder, ex.Status.ProviderStatus.Raw, &status)).To(Succeed())
+
+	return status.Plan
+}
+
+// getProviderStatus returns the decoded provider status of the given extension
+// resource.
+func getProviderStatus(ex *extensionsv1alpha1.Extension) config.ExampleStatus {
+	Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ex), ex)).To(Succeed())
+	Expect(ex.Status.ProviderStatus).NotTo(BeNil())
+
+	var status config.ExampleStatus
+	decoder := serializer.NewCodecFactory(scheme.Scheme, serializer.EnableStrict).UniversalDecoder()
+	Expect(runtime.DecodeInto(decoder, ex.Status.ProviderStatus.Raw, &status)).To(Succeed())
+
+	return status
+}
+
+// requestOperation requests the given custom operation via annotation on the
+// given extension resource.
+func requestOperation(ex *extensionsv1alpha1.Extension, op string) {
+	patch := client.MergeFrom(ex.DeepCopy())
+	metav1.SetMetaDataAnnotation(&ex.ObjectMeta, exampleactuator.AnnotationOperation, op)
+	Expect(k8sClient.Patch(ctx, ex, patch)).To(Succeed())
+}
+
+// markManagedResource annotates the ManagedResource with the given key, so that
+// tests can detect whether it has been re-created.
+func markManagedResource(key client.ObjectKey) {
+	mr := &resourcesv1alpha1.ManagedResource{}
+	Expect(k8sClient.Get(ctx, key, mr)).To(Succeed())
+	patch := client.MergeFrom(mr.DeepCopy())
+	metav1.SetMetaDataAnnotation(&mr.ObjectMeta, "test/marker", "true")
+	Expect(k8sClient.Patch(ctx, mr, patch, client.FieldOwner("test"))).To(Succeed())
+}
+
+// getErrorCodes returns the error codes of the given error returned by the
+// actuator. The error must carry a requeue hint, so that it is not retried
+// with exponential backoff.
+func getErrorCodes(err error) []corev1beta1.ErrorCode {
+	Expect(err).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+
+	return v1beta1helper.ExtractErrorCodes(reconcilerutils.ReconcileErrCause(err))
+}
+
 var _ = Describe("Actuator", Ordered, func() {
 	var (
 		// Contain the serialized cloud profile, seed and shoot and provider config
@@ -142,9 +314,11 @@ var _ = Describe("Actuator", Ordered, func() {
 		}
 
 		Expect(k8sClient.Create(ctx, cluster)).To(Succeed())
+		Expect(k8sClient.Create(ctx, extResource)).To(Succeed())
 	})
 
 	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, extResource)).To(Succeed())
 		Expect(k8sClient.Delete(ctx, cluster)).To(Succeed())
 	})
 
@@ -162,14 +336,16 @@ var _ = Describe("Actuator", Ordered, func() {
 	It("should fail to reconcile when no cluster exists", func() {
 		// Change namespace of the extension resource, so that a
 		// non-existing cluster is looked up.
-		extResource.Namespace = "non-existing-namespace"
+		ex := extResource.DeepCopy()
+		ex.Namespace = "non-existing-namespace"
 
 		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(act).NotTo(BeNil())
-		err = act.Reconcile(ctx, logger, extResource)
+		err = act.Reconcile(ctx, logger, ex)
 		Expect(err).Should(HaveOccurred())
 		Expect(err).To(MatchError(ContainSubstring("failed to get cluster")))
+		Expect(getErrorCodes(err)).To(ConsistOf(corev1beta1.ErrorRetryableInfraDependencies))
 	})
 
 	It("should fail to reconcile without provider config", func() {
@@ -180,6 +356,7 @@ var _ = Describe("Actuator", Ordered, func() {
 		err = act.Reconcile(ctx, logger, extResource)
 		Expect(err).Should(HaveOccurred())
 		Expect(err).To(MatchError(ContainSubstring("no provider config specified")))
+		Expect(getErrorCodes(err)).To(ConsistOf(corev1beta1.ErrorConfigurationProblem))
 	})
 
 	It("should succeed on Reconcile", func() {
@@ -193,18 +370,662 @@ var _ = Describe("Actuator", Ordered, func() {
 		Expect(act).NotTo(BeNil())
 		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
 
+		Expect(getHibernationPhase(extResource)).To(Equal(config.HibernationPhaseAwake))
+		Expect(getWorkloadReplicas(shootNamespace.Name)).To(Equal(int32(1)))
+
 		// TODO(user): Add more tests
 	})
 
+	It("should enrich the logger with shoot metadata", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+
+		var lines []string
+		logger := funcr.New(func(prefix, args string) {
+			lines = append(lines, args)
+		}, funcr.Options{})
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		Expect(lines).To(ContainElement(SatisfyAll(
+			ContainSubstring(`"msg"="reconciling extension"`),
+			ContainSubstring(`"generation"=1`),
+			ContainSubstring(`"shoot"="local"`),
+			ContainSubstring(`"project"="local"`),
+			ContainSubstring(`"seed"="local"`),
+		)))
+
+		lines = nil
+		Expect(act.Delete(ctx, logger, extResource)).To(Succeed())
+		Expect(lines).To(ContainElement(SatisfyAll(
+			ContainSubstring(`"msg"="deleting resources managed by extension"`),
+			ContainSubstring(`"shoot"="local"`),
+		)))
+	})
+
+	It("should project referenced secrets into the workload", func() {
+		cfg := providerConfig.DeepCopy()
+		cfg.Spec.SecretRefs = []config.SecretReference{{Name: "api-token"}}
+		data, err := json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: data,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act).NotTo(BeNil())
+
+		// The secret is not referenced by the shoot yet
+		err = act.Reconcile(ctx, logger, extResource)
+		Expect(err).To(MatchError(ContainSubstring("secret reference api-token not found in shoot resources")))
+		Expect(getErrorCodes(err)).To(ConsistOf(corev1beta1.ErrorConfigurationProblem))
+
+		shootWithResources := shoot.DeepCopy()
+		shootWithResources.Spec.Resources = []corev1beta1.NamedResourceReference{
+			{
+				Name: "api-token",
+				ResourceRef: autoscalingv1.CrossVersionObjectReference{
+					APIVersion: "v1",
+					Kind:       "Secret",
+					Name:       "my-api-token",
+				},
+			},
+		}
+		shootWithResourcesData, err := json.Marshal(shootWithResources)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.Shoot.Raw = shootWithResourcesData
+		Expect(k8sClient.Update(ctx, cluster)).To(Succeed())
+
+		// The secret has not been copied by gardenlet yet
+		err = act.Reconcile(ctx, logger, extResource)
+		Expect(err).To(MatchError(ContainSubstring("failed to get referenced secret api-token")))
+		Expect(getErrorCodes(err)).To(ConsistOf(corev1beta1.ErrorRetryableInfraDependencies))
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ref-my-api-token",
+				Namespace: shootNamespace.Name,
+			},
+			Data: map[string][]byte{
+				"token": []byte("s3cr3t"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		})
+
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		deployment := getWorkload(shootNamespace.Name)
+		Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+			Name: "secret-api-token",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: "ref-my-api-token",
+				},
+			},
+		}))
+		Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "secret-api-token",
+			MountPath: "/etc/example/secrets/api-token",
+			ReadOnly:  true,
+		}))
+		Expect(deployment.Spec.Template.Annotations).To(HaveKey("checksum/secret-api-token"))
+
+		// Resource references may be named longer than a volume name
+		longName := "api-token." + strings.Repeat("x", 100)
+		cfg.Spec.SecretRefs = append(cfg.Spec.SecretRefs, config.SecretReference{Name: longName})
+		data, err = json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: data,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		shootWithResources.Spec.Resources = append(shootWithResources.Spec.Resources, corev1beta1.NamedResourceReference{
+			Name: longName,
+			ResourceRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "v1",
+				Kind:       "Secret",
+				Name:       "my-api-token",
+			},
+		})
+		shootWithResourcesData, err = json.Marshal(shootWithResources)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.Shoot.Raw = shootWithResourcesData
+		Expect(k8sClient.Update(ctx, cluster)).To(Succeed())
+
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		deployment = getWorkload(shootNamespace.Name)
+		Expect(deployment.Spec.Template.Spec.Containers[0].VolumeMounts).To(ContainElement(SatisfyAll(
+			HaveField("Name", SatisfyAll(HavePrefix("secret-api-token-xxx"), HaveLen(63))),
+			HaveField("MountPath", "/etc/example/secrets/"+longName),
+		)))
+	})
+
+	It("should scale down components on hibernation and restore them on wake-up", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act).NotTo(BeNil())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getHibernationPhase(extResource)).To(Equal(config.HibernationPhaseAwake))
+		markComponentsHealthy(shootNamespace.Name)
+
+		// Hibernate the shoot
+		hibernatedShoot := shoot.DeepCopy()
+		hibernatedShoot.Spec.Hibernation = &corev1beta1.Hibernation{Enabled: ptr.To(true)}
+		hibernatedShootData, err := json.Marshal(hibernatedShoot)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.Shoot.Raw = hibernatedShootData
+		Expect(k8sClient.Update(ctx, cluster)).To(Succeed())
+
+		// The workload is scaled down first, and we should be waiting
+		// for it to become healthy.
+		err = act.Reconcile(ctx, logger, extResource)
+		Expect(err).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(err).To(MatchError(ContainSubstring("waiting for component " + exampleactuator.ComponentWorkload)))
+		Expect(getHibernationPhase(extResource)).To(Equal(config.HibernationPhaseHibernating))
+		Expect(getWorkloadReplicas(shootNamespace.Name)).To(Equal(int32(0)))
+
+		markComponentsHealthy(shootNamespace.Name)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getHibernationPhase(extResource)).To(Equal(config.HibernationPhaseHibernated))
+
+		// Subsequent reconciliations keep the components scaled down
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getHibernationPhase(extResource)).To(Equal(config.HibernationPhaseHibernated))
+		Expect(getWorkloadReplicas(shootNamespace.Name)).To(Equal(int32(0)))
+
+		// Wake up the shoot
+		cluster.Spec.Shoot.Raw = shootData
+		Expect(k8sClient.Update(ctx, cluster)).To(Succeed())
+
+		err = act.Reconcile(ctx, logger, extResource)
+		Expect(err).To(BeAssignableToTypeOf(&reconcilerutils.RequeueAfterError{}))
+		Expect(err).To(MatchError(ContainSubstring("waiting for component " + exampleactuator.ComponentWorkload)))
+		Expect(getHibernationPhase(extResource)).To(Equal(config.HibernationPhaseWakingUp))
+		Expect(getWorkloadReplicas(shootNamespace.Name)).To(Equal(int32(1)))
+
+		markComponentsHealthy(shootNamespace.Name)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getHibernationPhase(extResource)).To(Equal(config.HibernationPhaseAwake))
+	})
+
+	It("should generate secrets and rotate the CA with the shoot", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act).NotTo(BeNil())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		caSecrets := listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)
+		Expect(caSecrets).To(HaveLen(1))
+		oldCA := caSecrets[0]
+		Expect(listGeneratedSecrets(shootNamespace.Name, exampleactuator.ServerSecretName)).To(HaveLen(1))
+		Expect(listGeneratedSecrets(shootNamespace.Name, exampleactuator.TokenSecretName)).To(HaveLen(1))
+
+		// The CA and the token are persisted in the extension state
+		state := getState(extResource)
+		Expect(state.Secrets).To(HaveLen(2))
+
+		volumes := getWorkload(shootNamespace.Name).Spec.Template.Spec.Volumes
+		Expect(volumes).To(ContainElements(
+			HaveField("Name", "ca"),
+			HaveField("Name", "tls"),
+			HaveField("Name", "token"),
+		))
+
+		// Prepare the CA rotation of the shoot
+		rotatingShoot := shoot.DeepCopy()
+		rotatingShoot.Status.Credentials = &corev1beta1.ShootCredentials{
+			Rotation: &corev1beta1.ShootCredentialsRotation{
+				CertificateAuthorities: &corev1beta1.CARotation{
+					Phase:              corev1beta1.RotationPreparing,
+					LastInitiationTime: ptr.To(metav1.Now()),
+				},
+			},
+		}
+		rotatingShootData, err := json.Marshal(rotatingShoot)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.Shoot.Raw = rotatingShootData
+		Expect(k8sClient.Update(ctx, cluster)).To(Succeed())
+
+		// Both the old and the new CA must be present during rotation
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		caSecrets = listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)
+		Expect(caSecrets).To(HaveLen(2))
+		Expect(caSecrets).To(ContainElement(HaveField("Name", oldCA.Name)))
+
+		// Complete the CA rotation of the shoot
+		rotatingShoot.Status.Credentials.Rotation.CertificateAuthorities.Phase = corev1beta1.RotationCompleting
+		rotatingShootData, err = json.Marshal(rotatingShoot)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Spec.Shoot.Raw = rotatingShootData
+		Expect(k8sClient.Update(ctx, cluster)).To(Succeed())
+
+		// The old CA must be cleaned up after the rotation is completed
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		caSecrets = listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)
+		Expect(caSecrets).To(HaveLen(1))
+		Expect(caSecrets[0].Name).NotTo(Equal(oldCA.Name))
+		Expect(getState(extResource).Secrets).NotTo(ContainElement(HaveField("Name", oldCA.Name)))
+	})
+
+	It("should restore persisted secrets", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act).NotTo(BeNil())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		caSecrets := listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)
+		Expect(caSecrets).To(HaveLen(1))
+		ca := caSecrets[0]
+
+		// Drop the secrets as if the shoot has been migrated to another seed
+		for _, secret := range listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName) {
+			Expect(k8sClient.Delete(ctx, &secret)).To(Succeed())
+		}
+
+		Expect(act.Restore(ctx, logger, extResource)).To(Succeed())
+		caSecrets = listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)
+		Expect(caSecrets).To(HaveLen(1))
+		Expect(caSecrets[0].Name).To(Equal(ca.Name))
+		Expect(caSecrets[0].Data).To(Equal(ca.Data))
+	})
+
+	It("should skip reconciliation when its inputs are unchanged", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		fakeClock := testclock.NewFakeClock(time.Now())
+		act, err := exampleactuator.New(k8sClient, append(actuatorOpts, exampleactuator.WithClock(fakeClock))...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act).NotTo(BeNil())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		lastFullReconcile := getLastFullReconcileTime(extResource)
+
+		// Nothing changed, so the reconciliation is skipped
+		fakeClock.Step(time.Minute)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getLastFullReconcileTime(extResource)).To(Equal(lastFullReconcile))
+
+		// A missing ManagedResource is detected as drift
+		mr := &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      exampleactuator.ManagedResourceName(exampleactuator.ComponentWorkload),
+				Namespace: shootNamespace.Name,
+			},
+		}
+		Expect(k8sClient.Delete(ctx, mr)).To(Succeed())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mr), mr)).To(Succeed())
+		Expect(getLastFullReconcileTime(extResource)).To(BeTemporally(">", lastFullReconcile))
+		lastFullReconcile = getLastFullReconcileTime(extResource)
+
+		// A full reconciliation is performed after the interval elapsed
+		fakeClock.Step(exampleactuator.DefaultFullReconcileInterval)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getLastFullReconcileTime(extResource)).To(BeTemporally(">", lastFullReconcile))
+	})
+
+	It("should apply the managed resources via server-side apply", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, append(actuatorOpts, exampleactuator.WithFullReconcileInterval(0))...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		mr := &resourcesv1alpha1.ManagedResource{}
+		key := client.ObjectKey{Namespace: shootNamespace.Name, Name: exampleactuator.ManagedResourceName(exampleactuator.ComponentWorkload)}
+		Expect(k8sClient.Get(ctx, key, mr)).To(Succeed())
+		Expect(mr.Labels).To(HaveKeyWithValue(exampleactuator.LabelKeyManagedBy, exampleactuator.FieldManager))
+		Expect(mr.Labels).To(HaveKeyWithValue(exampleactuator.LabelKeyComponent, exampleactuator.ComponentWorkload))
+		Expect(mr.ManagedFields).To(ContainElement(And(
+			HaveField("Manager", exampleactuator.FieldManager),
+			HaveField("Operation", metav1.ManagedFieldsOperationApply),
+		)))
+
+		// Fields owned by other field managers are kept
+		patch := client.MergeFrom(mr.DeepCopy())
+		metav1.SetMetaDataLabel(&mr.ObjectMeta, "team", "example")
+		Expect(k8sClient.Patch(ctx, mr, patch, client.FieldOwner("other-controller"))).To(Succeed())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(k8sClient.Get(ctx, key, mr)).To(Succeed())
+		Expect(mr.Labels).To(HaveKeyWithValue("team", "example"))
+
+		// Conflicting fields are not overwritten
+		patch = client.MergeFrom(mr.DeepCopy())
+		mr.Spec.KeepObjects = ptr.To(true)
+		Expect(k8sClient.Patch(ctx, mr, patch, client.FieldOwner("other-controller"))).To(Succeed())
+		err = act.Reconcile(ctx, logger, extResource)
+		Expect(reconcilerutils.ReconcileErrCause(err)).To(MatchError(exampleactuator.ErrApplyConflict))
+		Expect(getErrorCodes(err)).To(ConsistOf(corev1beta1.ErrorConfigurationProblem))
+		Expect(k8sClient.Get(ctx, key, mr)).To(Succeed())
+		Expect(mr.Spec.KeepObjects).To(Equal(ptr.To(true)))
+
+		// The ManagedResource is recreated once the conflict is resolved
+		Expect(k8sClient.Delete(ctx, mr)).To(Succeed())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+	})
+
+	It("should prune managed resources of components, which are no longer rendered", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		obsolete := &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      exampleactuator.ManagedResourceName("obsolete"),
+				Namespace: shootNamespace.Name,
+				Labels: map[string]string{
+					exampleactuator.LabelKeyManagedBy: exampleactuator.FieldManager,
+					exampleactuator.LabelKeyComponent: "obsolete",
+				},
+			},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				SecretRefs: []corev1.LocalObjectReference{},
+			},
+		}
+		foreign := &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foreign",
+				Namespace: shootNamespace.Name,
+			},
+			Spec: resourcesv1alpha1.ManagedResourceSpec{
+				SecretRefs: []corev1.LocalObjectReference{},
+			},
+		}
+		Expect(k8sClient.Create(ctx, obsolete)).To(Succeed())
+		Expect(k8sClient.Create(ctx, foreign)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(obsolete), obsolete)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(foreign), foreign)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, foreign)).To(Succeed())
+	})
+
+	It("should perform custom operations requested via the Extension", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		markComponentsHealthy(shootNamespace.Name)
+
+		// Collect diagnostics
+		requestOperation(extResource, exampleactuator.OperationCollectDiagnostics)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		status := getProviderStatus(extResource)
+		Expect(extResource.Annotations).NotTo(HaveKey(exampleactuator.AnnotationOperation))
+		Expect(status.LastCustomOperation).NotTo(BeNil())
+		Expect(status.LastCustomOperation.Operation).To(Equal(exampleactuator.OperationCollectDiagnostics))
+		Expect(status.LastCustomOperation.Source).To(Equal(exampleactuator.OperationSourceExtension))
+		Expect(status.LastCustomOperation.State).To(Equal(config.CustomOperationStateSucceeded))
+		Expect(status.LastCustomOperation.Details).To(ContainElement(
+			HavePrefix("component " + exampleactuator.ComponentWorkload + ": ResourcesHealthy=True"),
+		))
+
+		// Rotate credentials
+		tokens := listGeneratedSecrets(shootNamespace.Name, exampleactuator.TokenSecretName)
+		Expect(tokens).To(HaveLen(1))
+		checksumKey := "checksum/secret-" + exampleactuator.TokenSecretName
+		Expect(getWorkload(shootNamespace.Name).Spec.Template.Annotations).To(HaveKey(checksumKey))
+		checksum := getWorkload(shootNamespace.Name).Spec.Template.Annotations[checksumKey]
+		requestOperation(extResource, exampleactuator.OperationRotateCredentials)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getProviderStatus(extResource).LastCustomOperation.State).To(Equal(config.CustomOperationStateSucceeded))
+		rotated := listGeneratedSecrets(shootNamespace.Name, exampleactuator.TokenSecretName)
+		Expect(rotated).To(HaveLen(1))
+		Expect(rotated[0].Data).NotTo(Equal(tokens[0].Data))
+
+		// The workload is rolled out, even if the secret keeps its name
+		Expect(getWorkload(shootNamespace.Name).Spec.Template.Annotations).To(HaveKeyWithValue(checksumKey, Not(Equal(checksum))))
+
+		// Re-create components
+		mr := &resourcesv1alpha1.ManagedResource{}
+		key := client.ObjectKey{Namespace: shootNamespace.Name, Name: exampleactuator.ManagedResourceName(exampleactuator.ComponentWorkload)}
+		markManagedResource(key)
+		requestOperation(extResource, exampleactuator.OperationRecreateComponents)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getProviderStatus(extResource).LastCustomOperation.State).To(Equal(config.CustomOperationStateSucceeded))
+		Expect(k8sClient.Get(ctx, key, mr)).To(Succeed())
+		Expect(mr.Annotations).NotTo(HaveKey("test/marker"))
+
+		// Unknown operations are recorded as failed
+		requestOperation(extResource, "unknown")
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		status = getProviderStatus(extResource)
+		Expect(extResource.Annotations).NotTo(HaveKey(exampleactuator.AnnotationOperation))
+		Expect(status.LastCustomOperation.Operation).To(Equal("unknown"))
+		Expect(status.LastCustomOperation.State).To(Equal(config.CustomOperationStateFailed))
+		Expect(status.LastCustomOperation.Description).To(ContainSubstring(exampleactuator.ErrUnknownOperation.Error()))
+	})
+
+	It("should perform custom operations requested via the Shoot once", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		annotatedShoot := shoot.DeepCopy()
+		metav1.SetMetaDataAnnotation(&annotatedShoot.ObjectMeta, exampleactuator.AnnotationOperation, exampleactuator.OperationRecreateComponents)
+		annotatedShootData, err := json.Marshal(annotatedShoot)
+		Expect(err).NotTo(HaveOccurred())
+		patch := client.MergeFrom(cluster.DeepCopy())
+		cluster.Spec.Shoot.Raw = annotatedShootData
+		Expect(k8sClient.Patch(ctx, cluster, patch)).To(Succeed())
+
+		mr := &resourcesv1alpha1.ManagedResource{}
+		key := client.ObjectKey{Namespace: shootNamespace.Name, Name: exampleactuator.ManagedResourceName(exampleactuator.ComponentWorkload)}
+		markManagedResource(key)
+
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		status := getProviderStatus(extResource)
+		Expect(status.LastCustomOperation).NotTo(BeNil())
+		Expect(status.LastCustomOperation.Source).To(Equal(exampleactuator.OperationSourceShoot))
+		Expect(status.LastCustomOperation.State).To(Equal(config.CustomOperationStateSucceeded))
+		Expect(status.ObservedShootOperation).To(Equal(exampleactuator.OperationRecreateComponents))
+		Expect(k8sClient.Get(ctx, key, mr)).To(Succeed())
+		Expect(mr.Annotations).NotTo(HaveKey("test/marker"))
+
+		// The operation is not repeated, while the annotation is unchanged
+		markManagedResource(key)
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(k8sClient.Get(ctx, key, mr)).To(Succeed())
+		Expect(mr.Annotations).To(HaveKey("test/marker"))
+
+		// The operation can be requested again, once the annotation has
+		// been removed
+		patch = client.MergeFrom(cluster.DeepCopy())
+		cluster.Spec.Shoot.Raw = shootData
+		Expect(k8sClient.Patch(ctx, cluster, patch)).To(Succeed())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getProviderStatus(extResource).ObservedShootOperation).To(BeEmpty())
+	})
+
+	It("should report planned updates in dry-run mode", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		markComponentsHealthy(shootNamespace.Name)
+		workload := getWorkload(shootNamespace.Name)
+
+		// Change the provider config and reconcile in dry-run mode
+		cfg := providerConfig.DeepCopy()
+		cfg.Spec.Foo = "baz"
+		cfgData, err := json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: cfgData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		dryRunAct, err := exampleactuator.New(k8sClient, append(actuatorOpts, exampleactuator.WithDryRun(true))...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dryRunAct.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		plan := getPlan(extResource)
+		Expect(plan).NotTo(BeNil())
+		Expect(plan.Operation).To(Equal("reconcile"))
+		Expect(plan.Changes).To(ContainElements(
+			config.PlannedChange{
+				Action:    config.PlannedActionUpdate,
+				Kind:      "ManagedResource",
+				Namespace: shootNamespace.Name,
+				Name:      exampleactuator.ManagedResourceName(exampleactuator.ComponentConfig),
+				Component: exampleactuator.ComponentConfig,
+			},
+			config.PlannedChange{
+				Action:    config.PlannedActionUpdate,
+				Kind:      "ConfigMap",
+				Namespace: shootNamespace.Name,
+				Name:      "example-config",
+				Component: exampleactuator.ComponentConfig,
+			},
+			config.PlannedChange{
+				Action:    config.PlannedActionUpdate,
+				Kind:      "Deployment",
+				Namespace: shootNamespace.Name,
+				Name:      "example",
+				Component: exampleactuator.ComponentWorkload,
+			},
+		))
+		for _, change := range plan.Changes {
+			Expect(change.Action).To(Equal(config.PlannedActionUpdate))
+		}
+
+		// Nothing has been changed in dry-run mode
+		Expect(getWorkload(shootNamespace.Name).Spec).To(Equal(workload.Spec))
+
+		// A regular reconciliation applies the changes and drops the plan
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+		Expect(getPlan(extResource)).To(BeNil())
+		Expect(getWorkload(shootNamespace.Name).Spec).NotTo(Equal(workload.Spec))
+	})
+
+	It("should not delete anything in dry-run mode", func() {
+		act, err := exampleactuator.New(k8sClient, append(actuatorOpts, exampleactuator.WithDryRun(true))...)
+		Expect(err).NotTo(HaveOccurred())
+
+		// The finalizer is released without deleting anything
+		Expect(act.Delete(ctx, logger, extResource)).To(Succeed())
+
+		plan := getPlan(extResource)
+		Expect(plan).NotTo(BeNil())
+		Expect(plan.Operation).To(Equal("delete"))
+		Expect(plan.Changes).To(ContainElement(config.PlannedChange{
+			Action:    config.PlannedActionDelete,
+			Kind:      "ManagedResource",
+			Namespace: shootNamespace.Name,
+			Name:      exampleactuator.ManagedResourceName(exampleactuator.ComponentWorkload),
+			Component: exampleactuator.ComponentWorkload,
+		}))
+		for _, change := range plan.Changes {
+			Expect(change.Action).To(Equal(config.PlannedActionDelete))
+		}
+
+		var items resourcesv1alpha1.ManagedResourceList
+		Expect(k8sClient.List(ctx, &items, client.InNamespace(shootNamespace.Name))).To(Succeed())
+		Expect(items.Items).To(HaveLen(2))
+		Expect(listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)).NotTo(BeEmpty())
+
+		// Forceful deletion is never blocked either
+		Expect(act.ForceDelete(ctx, logger, extResource)).To(Succeed())
+		Expect(getPlan(extResource).Operation).To(Equal("force-delete"))
+		Expect(k8sClient.List(ctx, &items, client.InNamespace(shootNamespace.Name))).To(Succeed())
+		Expect(items.Items).To(HaveLen(2))
+	})
+
 	It("should succeed on Delete", func() {
 		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(act).NotTo(BeNil())
 		Expect(act.Delete(ctx, logger, extResource)).To(Succeed())
 
+		var items resourcesv1alpha1.ManagedResourceList
+		Expect(k8sClient.List(ctx, &items, client.InNamespace(shootNamespace.Name))).To(Succeed())
+		Expect(items.Items).To(BeEmpty())
+
+		Expect(listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)).To(BeEmpty())
+		Expect(listGeneratedSecrets(shootNamespace.Name, exampleactuator.TokenSecretName)).To(BeEmpty())
+
 		// TODO(user): Add more tests
 	})
 
+	It("should report planned creations in dry-run mode", func() {
+		extResource.Spec.ProviderConfig = &runtime.RawExtension{
+			Raw: providerConfigData,
+		}
+		Expect(k8sClient.Update(ctx, extResource)).To(Succeed())
+
+		act, err := exampleactuator.New(k8sClient, append(actuatorOpts, exampleactuator.WithDryRun(true))...)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(act.Reconcile(ctx, logger, extResource)).To(Succeed())
+
+		plan := getPlan(extResource)
+		Expect(plan).NotTo(BeNil())
+		Expect(plan.Changes).NotTo(BeEmpty())
+		for _, change := range plan.Changes {
+			Expect(change.Action).To(Equal(config.PlannedActionCreate))
+		}
+		Expect(plan.Changes).To(ContainElement(config.PlannedChange{
+			Action:    config.PlannedActionCreate,
+			Kind:      "Deployment",
+			Namespace: shootNamespace.Name,
+			Name:      "example",
+			Component: exampleactuator.ComponentWorkload,
+		}))
+
+		// Neither components nor secrets have been created
+		var items resourcesv1alpha1.ManagedResourceList
+		Expect(k8sClient.List(ctx, &items, client.InNamespace(shootNamespace.Name))).To(Succeed())
+		Expect(items.Items).To(BeEmpty())
+		Expect(listGeneratedSecrets(shootNamespace.Name, exampleactuator.CASecretName)).To(BeEmpty())
+	})
+
 	It("should succeed on ForceDelete", func() {
 		act, err := exampleactuator.New(k8sClient, actuatorOpts...)
 		Expect(err).NotTo(HaveOccurred())
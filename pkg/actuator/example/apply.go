@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// FieldManager is the name of the field manager used by the [Actuator]
+	// for server-side apply of the objects it deploys.
+	FieldManager = "gardener-extension-example"
+
+	// LabelKeyManagedBy is the key of the label, which marks the objects
+	// applied by the [Actuator]. Its value is the [FieldManager].
+	LabelKeyManagedBy = "app.kubernetes.io/managed-by"
+
+	// LabelKeyComponent is the key of the label, which specifies the name
+	// of the component an object applied by the [Actuator] belongs to.
+	LabelKeyComponent = "example.extensions.gardener.cloud/component"
+)
+
+// ErrApplyConflict is an error, which is returned when applying an object
+// conflicts with fields owned by another field manager.
+var ErrApplyConflict = errors.New("apply conflict")
+
+// ApplyConflictError is returned when applying an object conflicts with fields
+// owned by other field managers. The conflicting fields are not overwritten,
+// so that other controllers or humans can co-own the object. The conflict must
+// be resolved by removing the fields from the other field managers.
+type ApplyConflictError struct {
+	// Kind is the kind of the object.
+	Kind string
+
+	// Key identifies the object.
+	Key client.ObjectKey
+
+	// Conflicts describes the conflicting fields and their field managers.
+	Conflicts []string
+}
+
+// Error implements the [error] interface.
+func (e *ApplyConflictError) Error() string {
+	return fmt.Sprintf("%s: %s %s: %s", ErrApplyConflict, e.Kind, e.Key, strings.Join(e.Conflicts, "; "))
+}
+
+// Is returns true, if the target is [ErrApplyConflict].
+func (e *ApplyConflictError) Is(target error) bool {
+	return target == ErrApplyConflict
+}
+
+// applier applies objects via server-side apply with a dedicated field
+// manager. Objects are labeled with the component they belong to, so that
+// objects, which are no longer rendered, can be pruned.
+type applier struct {
+	client       client.Client
+	fieldManager string
+
+	// legacyFieldManagers are the names of the field managers, which wrote
+	// the objects via client-side create and update calls previously.
+	// Their fields are migrated to the field manager of the applier.
+	legacyFieldManagers sets.Set[string]
+}
+
+// newApplier returns a new [applier], which uses the [FieldManager]. The
+// fields written via client-side create and update calls by previous versions
+// of the extension are owned by a field manager named after the binary, since
+// the API server derives the name from the default user agent of the client.
+func newApplier(c client.Client) *applier {
+	ap := &applier{
+		client:              c,
+		fieldManager:        FieldManager,
+		legacyFieldManagers: sets.New(filepath.Base(os.Args[0])),
+	}
+
+	return ap
+}
+
+// apply applies the given object, which belongs to the component with the
+// given name. Conflicts with fields owned by other field managers are
+// returned as [ApplyConflictError].
+func (ap *applier) apply(ctx context.Context, obj client.Object, componentName string) error {
+	gvk, err := apiutil.GVKForObject(obj, ap.client.Scheme())
+	if err != nil {
+		return fmt.Errorf("failed to get kind of object %s: %w", obj.GetName(), err)
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[LabelKeyManagedBy] = ap.fieldManager
+	labels[LabelKeyComponent] = componentName
+	obj.SetLabels(labels)
+
+	if err := ap.upgradeManagedFields(ctx, obj); err != nil {
+		return err
+	}
+
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+
+	u := &unstructured.Unstructured{Object: data}
+	u.SetGroupVersionKind(gvk)
+	u.SetResourceVersion("")
+	u.SetManagedFields(nil)
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "status")
+
+	err = ap.client.Apply(ctx, client.ApplyConfigurationFromUnstructured(u), client.FieldOwner(ap.fieldManager))
+	if conflicts := fieldManagerConflicts(err); len(conflicts) > 0 {
+		return &ApplyConflictError{
+			Kind:      gvk.Kind,
+			Key:       client.ObjectKeyFromObject(obj),
+			Conflicts: conflicts,
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply %s %s: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+	}
+
+	return nil
+}
+
+// upgradeManagedFields migrates the fields of the given object, which are
+// owned by the legacy field managers, to the field manager of the [applier],
+// so that they do not cause conflicts when the object is applied.
+func (ap *applier) upgradeManagedFields(ctx context.Context, obj client.Object) error {
+	existing, ok := obj.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+
+	if err := ap.client.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, ap.legacyFieldManagers, ap.fieldManager)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade managed fields of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+	if patch == nil {
+		return nil
+	}
+
+	if err := ap.client.Patch(ctx, existing, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		return fmt.Errorf("failed to upgrade managed fields of %s: %w", client.ObjectKeyFromObject(obj), err)
+	}
+
+	return nil
+}
+
+// prune deletes the objects of the given list type in the given namespace,
+// which have been applied by the [applier], but belong to none of the given
+// components.
+func (ap *applier) prune(ctx context.Context, list client.ObjectList, namespace string, componentNames ...string) error {
+	err := ap.client.List(
+		ctx,
+		list,
+		client.InNamespace(namespace),
+		client.MatchingLabels{LabelKeyManagedBy: ap.fieldManager},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list objects for pruning: %w", err)
+	}
+
+	keep := sets.New(componentNames...)
+
+	return meta.EachListItem(list, func(o runtime.Object) error {
+		obj, ok := o.(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", o)
+		}
+
+		if keep.Has(obj.GetLabels()[LabelKeyComponent]) {
+			return nil
+		}
+
+		if err := ap.client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to prune %s: %w", client.ObjectKeyFromObject(obj), err)
+		}
+
+		return nil
+	})
+}
+
+// fieldManagerConflicts returns the field manager conflicts reported by the
+// API server for the given error of an apply request.
+func fieldManagerConflicts(err error) []string {
+	if !apierrors.IsConflict(err) {
+		return nil
+	}
+
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+
+	conflicts := make([]string, 0)
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s", cause.Field, cause.Message))
+		}
+	}
+
+	return conflicts
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/component-base/featuregate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"gardener-extension-example/pkg/apis/config"
+	"gardener-extension-example/pkg/version"
+)
+
+// DefaultFullReconcileInterval is the default interval after which a full
+// reconciliation is performed, even if none of its inputs changed, e.g. in
+// order to renew the certificates of the seed-side components.
+const DefaultFullReconcileInterval = time.Hour
+
+// reconcileInputs are the inputs of a full reconciliation, which are covered
+// by the checksum recorded in the provider status.
+type reconcileInputs struct {
+	Version           string                              `json:"version"`
+	GardenerVersion   string                              `json:"gardenerVersion"`
+	GardenletFeatures map[featuregate.Feature]bool        `json:"gardenletFeatures,omitempty"`
+	Image             string                              `json:"image"`
+	Config            config.ExampleConfigSpec            `json:"config"`
+	Hibernated        bool                                `json:"hibernated"`
+	Replicas          int                                 `json:"replicas"`
+	Credentials       *gardencorev1beta1.ShootCredentials `json:"credentials,omitempty"`
+	Secrets           []referencedSecretInput             `json:"secrets,omitempty"`
+}
+
+// referencedSecretInput is a secret referenced by the [config.ExampleConfig],
+// which is covered by the checksum.
+type referencedSecretInput struct {
+	Name       string `json:"name"`
+	SecretName string `json:"secretName"`
+	Checksum   string `json:"checksum"`
+}
+
+// computeChecksum returns the checksum over the inputs of a full
+// reconciliation, i.e. the effective provider config, the relevant fields of
+// the cluster, the referenced secrets and the version of the extension.
+func (a *Actuator) computeChecksum(
+	ctx context.Context,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	cfg config.ExampleConfig,
+) (string, error) {
+	secrets, err := a.getReferencedSecrets(ctx, ex.Namespace, cluster, cfg)
+	if err != nil {
+		return "", err
+	}
+
+	inputs := reconcileInputs{
+		Version:           version.Version,
+		GardenerVersion:   a.gardenerVersion,
+		GardenletFeatures: a.gardenletFeatureGates,
+		Image:             a.image,
+		Config:            cfg.Spec,
+		Hibernated:        extensionscontroller.IsHibernationEnabled(cluster),
+		Replicas:          extensionscontroller.GetReplicas(cluster, 1),
+		Secrets:           make([]referencedSecretInput, 0, len(secrets)),
+	}
+
+	if cluster.Shoot != nil {
+		inputs.Credentials = cluster.Shoot.Status.Credentials
+	}
+
+	for _, s := range secrets {
+		item := referencedSecretInput{
+			Name:       s.name,
+			SecretName: s.secretName,
+			Checksum:   s.checksum,
+		}
+		inputs.Secrets = append(inputs.Secrets, item)
+	}
+
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal reconcile inputs: %w", err)
+	}
+
+	return utils.ComputeSHA256Hex(data), nil
+}
+
+// needsFullReconcile returns the reason, why a full reconciliation of the
+// given [extensionsv1alpha1.Extension] resource is needed, or an empty string,
+// if the reconciliation can be skipped. A reconciliation is skipped only if
+// the given checksum matches the checksum of the last full reconciliation, and
+// cheap drift checks, e.g. for the existence of the ManagedResources, pass.
+func (a *Actuator) needsFullReconcile(
+	ctx context.Context,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	status *config.ExampleStatus,
+	checksum string,
+) (string, error) {
+	switch {
+	case a.fullReconcileInterval <= 0:
+		return "skipping reconciliations is disabled", nil
+	case status.Checksum == "":
+		return "no checksum recorded", nil
+	case status.Checksum != checksum:
+		return "inputs changed", nil
+	case status.LastFullReconcileTime == nil:
+		return "no full reconciliation recorded", nil
+	case a.clock.Since(status.LastFullReconcileTime.Time) >= a.fullReconcileInterval:
+		return "full reconcile interval elapsed", nil
+	}
+
+	phase := config.HibernationPhaseAwake
+	if extensionscontroller.IsHibernationEnabled(cluster) {
+		phase = config.HibernationPhaseHibernated
+	}
+	if status.HibernationPhase != phase {
+		return fmt.Sprintf("hibernation phase is %q instead of %q", status.HibernationPhase, phase), nil
+	}
+
+	for _, c := range components {
+		mr := &resourcesv1alpha1.ManagedResource{}
+		key := client.ObjectKey{Namespace: ex.Namespace, Name: ManagedResourceName(c.name)}
+		if err := a.client.Get(ctx, key, mr); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Sprintf("managed resource %s not found", key.Name), nil
+			}
+
+			return "", fmt.Errorf("failed to get managed resource %s: %w", key, err)
+		}
+
+		if mr.DeletionTimestamp != nil {
+			return fmt.Sprintf("managed resource %s is being deleted", key.Name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// recordChecksum records the given checksum of a full reconciliation in the
+// provider status of the given [extensionsv1alpha1.Extension] resource. An
+// empty checksum invalidates the checksum of the last full reconciliation.
+func (a *Actuator) recordChecksum(ctx context.Context, ex *extensionsv1alpha1.Extension, checksum string) error {
+	status, err := a.getStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	if checksum == "" && status.Checksum == "" {
+		return nil
+	}
+
+	status.Checksum = checksum
+	status.LastFullReconcileTime = nil
+	if checksum != "" {
+		now := metav1.NewTime(a.clock.Now())
+		status.LastFullReconcileTime = &now
+	}
+
+	return a.updateStatus(ctx, ex, status)
+}
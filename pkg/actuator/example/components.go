@@ -0,0 +1,346 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	"github.com/gardener/gardener/pkg/resourcemanager/controller/garbagecollector/references"
+	"github.com/gardener/gardener/pkg/utils"
+	kubernetesutils "github.com/gardener/gardener/pkg/utils/kubernetes"
+	"github.com/gardener/gardener/pkg/utils/managedresources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"gardener-extension-example/pkg/apis/config"
+)
+
+const (
+	// ComponentConfig is the name of the component, which provides the
+	// configuration for the seed-side workload.
+	ComponentConfig = "config"
+	// ComponentWorkload is the name of the component, which provides the
+	// seed-side workload.
+	ComponentWorkload = "workload"
+
+	// DefaultImage is the default image used by the seed-side workload.
+	//
+	// TODO(user): replace with the image of your seed-side workload
+	DefaultImage = "registry.k8s.io/pause:3.10"
+
+	// ManagedResourceNamePrefix is the prefix used for the names of the
+	// ManagedResources created by the [Actuator].
+	ManagedResourceNamePrefix = "extension-example-"
+
+	// configMapName is the name of the ConfigMap, which provides the
+	// configuration for the seed-side workload.
+	configMapName = "example-config"
+
+	// deploymentName is the name of the seed-side workload.
+	deploymentName = "example"
+
+	// deleteTimeout is the max duration to wait for ManagedResources to be
+	// deleted.
+	deleteTimeout = 2 * time.Minute
+)
+
+// component is a seed-side component, which is deployed by the [Actuator] into
+// the shoot namespace via a ManagedResource.
+type component struct {
+	// name is the name of the component.
+	name string
+
+	// objects returns the objects of the component rendered with the given
+	// [componentValues].
+	objects func(v componentValues) []client.Object
+}
+
+// componentValues provides the values used for rendering the objects of a
+// [component].
+type componentValues struct {
+	namespace string
+	config    config.ExampleConfig
+	image     string
+	replicas  int32
+	secrets   []referencedSecret
+	generated generatedSecrets
+}
+
+// components are the seed-side components managed by the [Actuator] in the
+// order in which they are restored on wake-up. On hibernation the components
+// are scaled down in reverse order.
+var components = []component{
+	{name: ComponentConfig, objects: configObjects},
+	{name: ComponentWorkload, objects: workloadObjects},
+}
+
+// ManagedResourceName returns the name of the ManagedResource for the
+// component with the given name.
+func ManagedResourceName(componentName string) string {
+	return ManagedResourceNamePrefix + componentName
+}
+
+// componentLabels returns the labels for the objects of the seed-side
+// components.
+func componentLabels() map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":    deploymentName,
+		"app.kubernetes.io/part-of": Name,
+	}
+}
+
+// configData returns the data of the ConfigMap for the seed-side workload.
+func configData(v componentValues) map[string]string {
+	return map[string]string{
+		"foo": v.config.Spec.Foo,
+	}
+}
+
+// configObjects returns the objects of the [ComponentConfig] component.
+func configObjects(v componentValues) []client.Object {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      configMapName,
+			Namespace: v.namespace,
+			Labels:    componentLabels(),
+		},
+		Data: configData(v),
+	}
+
+	return []client.Object{cm}
+}
+
+// workloadObjects returns the objects of the [ComponentWorkload] component.
+func workloadObjects(v componentValues) []client.Object {
+	labels := componentLabels()
+	annotations := map[string]string{
+		"checksum/configmap-" + configMapName: utils.ComputeConfigMapChecksum(configData(v)),
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: configMapName,
+					},
+				},
+			},
+		},
+	}
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "config",
+			MountPath: "/etc/example",
+			ReadOnly:  true,
+		},
+	}
+
+	// Mount the secrets generated by the secrets manager. Rotated secrets
+	// may be regenerated under the same name, so the checksums of their data
+	// are annotated in order to trigger a rollout of the workload.
+	generated := []struct {
+		name       string
+		config     string
+		secretName string
+		mountPath  string
+	}{
+		{name: "ca", config: CASecretName, secretName: v.generated.caBundle, mountPath: "/etc/example/ca"},
+		{name: "tls", config: ServerSecretName, secretName: v.generated.server, mountPath: "/etc/example/tls"},
+		{name: "token", config: TokenSecretName, secretName: v.generated.token, mountPath: "/etc/example/token"},
+	}
+	for _, item := range generated {
+		if item.secretName == "" {
+			continue
+		}
+
+		if checksum, ok := v.generated.checksums[item.config]; ok {
+			annotations["checksum/secret-"+item.config] = checksum
+		}
+
+		volumes = append(volumes, corev1.Volume{
+			Name: item.name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: item.secretName,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      item.name,
+			MountPath: item.mountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	// Project the referenced secrets into the workload
+	for _, secret := range v.secrets {
+		annotations["checksum/"+secret.volumeName] = secret.checksum
+		volumes = append(volumes, corev1.Volume{
+			Name: secret.volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secret.secretName,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      secret.volumeName,
+			MountPath: path.Join(secretsMountPath, secret.name),
+			ReadOnly:  true,
+		})
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deploymentName,
+			Namespace: v.namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:             ptr.To(v.replicas),
+			RevisionHistoryLimit: ptr.To[int32](2),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: ptr.To(false),
+					Containers: []corev1.Container{
+						{
+							Name:            deploymentName,
+							Image:           v.image,
+							ImagePullPolicy: corev1.PullIfNotPresent,
+							VolumeMounts:    volumeMounts,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+
+	return []client.Object{deployment}
+}
+
+// deployComponent renders the objects of the given [component] and deploys
+// them via a ManagedResource in the namespace specified by the values. The
+// ManagedResource and its secret are applied via server-side apply, so that
+// other controllers or humans can co-own their fields.
+func (a *Actuator) deployComponent(ctx context.Context, c component, v componentValues) error {
+	registry := managedresources.NewRegistry(kubernetes.SeedScheme, kubernetes.SeedCodec, kubernetes.SeedSerializer)
+	data, err := registry.AddAllAndSerialize(c.objects(v)...)
+	if err != nil {
+		return fmt.Errorf("failed to serialize objects of component %s: %w", c.name, err)
+	}
+
+	name := ManagedResourceName(c.name)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      managedresources.SecretPrefix + name,
+			Namespace: v.namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+
+	// Make the secret immutable with a unique name based on its data. The
+	// secrets of previous reconciliations are garbage collected by the
+	// gardener-resource-manager, once they are no longer referenced.
+	if err := kubernetesutils.MakeUnique(secret); err != nil {
+		return fmt.Errorf("failed to make secret of component %s unique: %w", c.name, err)
+	}
+
+	mr := &resourcesv1alpha1.ManagedResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: v.namespace,
+		},
+		Spec: resourcesv1alpha1.ManagedResourceSpec{
+			Class: ptr.To(v1beta1constants.SeedResourceManagerClass),
+			SecretRefs: []corev1.LocalObjectReference{
+				{Name: secret.Name},
+			},
+			KeepObjects: ptr.To(false),
+		},
+	}
+
+	if err := references.InjectAnnotations(mr); err != nil {
+		return fmt.Errorf("failed to inject references into managed resource of component %s: %w", c.name, err)
+	}
+
+	ap := newApplier(a.client)
+	if err := ap.apply(ctx, secret, c.name); err != nil {
+		return fmt.Errorf("failed to deploy component %s: %w", c.name, err)
+	}
+
+	if err := ap.apply(ctx, mr, c.name); err != nil {
+		return fmt.Errorf("failed to deploy component %s: %w", c.name, err)
+	}
+
+	return nil
+}
+
+// pruneComponents deletes the ManagedResources in the given namespace, which
+// have been applied by the [Actuator], but whose components are no longer
+// rendered. Their secrets are garbage collected by the
+// gardener-resource-manager.
+func (a *Actuator) pruneComponents(ctx context.Context, namespace string) error {
+	names := make([]string, 0, len(components))
+	for _, c := range components {
+		names = append(names, c.name)
+	}
+
+	ap := newApplier(a.client)
+	if err := ap.prune(ctx, &resourcesv1alpha1.ManagedResourceList{}, namespace, names...); err != nil {
+		return fmt.Errorf("failed to prune components: %w", err)
+	}
+
+	return nil
+}
+
+// deleteComponent deletes the ManagedResource of the given [component] and
+// waits for it to be gone.
+func (a *Actuator) deleteComponent(ctx context.Context, c component, namespace string) error {
+	name := ManagedResourceName(c.name)
+	if err := managedresources.DeleteForSeed(ctx, a.client, namespace, name); err != nil {
+		return fmt.Errorf("failed to delete component %s: %w", c.name, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, deleteTimeout)
+	defer cancel()
+
+	if err := managedresources.WaitUntilDeleted(timeoutCtx, a.client, namespace, name); err != nil {
+		return fmt.Errorf("failed waiting for component %s to be deleted: %w", c.name, err)
+	}
+
+	return nil
+}
+
+// deleteComponents deletes the seed-side components from the given namespace
+// in reverse order.
+func (a *Actuator) deleteComponents(ctx context.Context, namespace string) error {
+	for _, c := range slices.Backward(components) {
+		if err := a.deleteComponent(ctx, c, namespace); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
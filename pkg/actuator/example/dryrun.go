@@ -0,0 +1,432 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils/managedresources"
+	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"gardener-extension-example/pkg/apis/config"
+)
+
+// dryRunClient is a [client.Client], which does not persist any changes. Since
+// objects created in dry-run mode do not exist afterwards, patching them
+// succeeds without any effect, like patching them after their creation would.
+type dryRunClient struct {
+	client.Client
+
+	created sets.Set[client.ObjectKey]
+}
+
+// newDryRunClient returns a new [client.Client], which wraps the given client
+// and does not persist any changes.
+func newDryRunClient(c client.Client) client.Client {
+	dc := &dryRunClient{
+		Client:  client.NewDryRunClient(c),
+		created: sets.New[client.ObjectKey](),
+	}
+
+	return dc
+}
+
+// Create creates the given object in dry-run mode and remembers it, so that
+// subsequent patches of it succeed.
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.Client.Create(ctx, obj, opts...); err != nil {
+		return err
+	}
+	c.created.Insert(client.ObjectKeyFromObject(obj))
+
+	return nil
+}
+
+// Patch patches the given object in dry-run mode, unless it has only been
+// created in dry-run mode.
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if c.created.Has(client.ObjectKeyFromObject(obj)) {
+		return nil
+	}
+
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+// reconcileDryRun computes the changes, which [Actuator.Reconcile] would
+// apply, and records them in the provider status of the given
+// [extensionsv1alpha1.Extension] resource and in the logs instead of applying
+// them.
+func (a *Actuator) reconcileDryRun(
+	ctx context.Context,
+	logger logr.Logger,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	cfg config.ExampleConfig,
+) error {
+	// The secrets manager creates and updates secrets while generating
+	// them, so it uses a client, which does not persist any changes.
+	sm, err := a.newSecretsManager(ctx, logger, newDryRunClient(a.client), cluster)
+	if err != nil {
+		return err
+	}
+
+	generated, err := generateAllSecrets(ctx, sm, ex.Namespace)
+	if err != nil {
+		return err
+	}
+
+	changes, err := a.planSecrets(ctx, ex.Namespace, sm)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := a.getReferencedSecrets(ctx, ex.Namespace, cluster, cfg)
+	if err != nil {
+		return err
+	}
+
+	values := componentValues{
+		namespace: ex.Namespace,
+		config:    cfg,
+		image:     a.image,
+		replicas:  int32(extensionscontroller.GetReplicas(cluster, 1)),
+		secrets:   secrets,
+		generated: generated,
+	}
+
+	for _, c := range components {
+		items, err := a.planComponent(ctx, logger, c, values)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, items...)
+	}
+
+	plan := &config.Plan{
+		Operation: "reconcile",
+		Changes:   changes,
+	}
+
+	return a.recordPlan(ctx, logger, ex, plan)
+}
+
+// deleteDryRun computes the changes, which [Actuator.Delete] would apply, and
+// records them in the provider status of the given
+// [extensionsv1alpha1.Extension] resource and in the logs. Nothing is deleted
+// in dry-run mode, but the finalizer is still released, so that the deletion
+// of the shoot does not hang. The objects left behind are reported by the
+// garbage collector and removed by it, once dry-run mode is disabled.
+func (a *Actuator) deleteDryRun(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension, operation string) error {
+	changes := make([]config.PlannedChange, 0)
+
+	for _, c := range slices.Backward(components) {
+		name := ManagedResourceName(c.name)
+		mr := &resourcesv1alpha1.ManagedResource{}
+		if err := a.client.Get(ctx, client.ObjectKey{Namespace: ex.Namespace, Name: name}, mr); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return fmt.Errorf("failed to get managed resource %s: %w", name, err)
+		}
+
+		objects, err := managedresources.GetObjects(ctx, a.client, ex.Namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to get objects of component %s: %w", c.name, err)
+		}
+
+		for _, obj := range objects {
+			change, err := a.plannedChange(config.PlannedActionDelete, c.name, obj)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+
+		change, err := a.plannedChange(config.PlannedActionDelete, c.name, mr)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+
+	var items corev1.SecretList
+	if err := a.client.List(ctx, &items, client.InNamespace(ex.Namespace), client.MatchingLabels(managedSecretLabels())); err != nil {
+		return fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	for _, secret := range items.Items {
+		change, err := a.plannedChange(config.PlannedActionDelete, "", &secret)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+
+	plan := &config.Plan{
+		Operation: operation,
+		Changes:   changes,
+	}
+
+	return a.recordPlan(ctx, logger, ex, plan)
+}
+
+// planSecrets computes the changes to the secrets generated by the given
+// [secretsmanager.Interface], i.e. secrets which would be created and obsolete
+// secrets, which would be deleted during cleanup.
+func (a *Actuator) planSecrets(ctx context.Context, namespace string, sm secretsmanager.Interface) ([]config.PlannedChange, error) {
+	desired := make(map[string]*corev1.Secret)
+	for _, sc := range secretConfigs(namespace) {
+		for _, class := range []secretsmanager.GetOption{secretsmanager.Current, secretsmanager.Old, secretsmanager.Bundle} {
+			if secret, found := sm.Get(sc.Config.GetName(), class); found {
+				desired[secret.Name] = secret
+			}
+		}
+	}
+
+	var items corev1.SecretList
+	if err := a.client.List(ctx, &items, client.InNamespace(namespace), client.MatchingLabels(managedSecretLabels())); err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	existing := sets.New[string]()
+	changes := make([]config.PlannedChange, 0)
+	for _, secret := range items.Items {
+		existing.Insert(secret.Name)
+		if _, ok := desired[secret.Name]; ok {
+			continue
+		}
+
+		change, err := a.plannedChange(config.PlannedActionDelete, "", &secret)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	for name, secret := range desired {
+		if existing.Has(name) {
+			continue
+		}
+
+		change, err := a.plannedChange(config.PlannedActionCreate, "", secret)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	slices.SortFunc(changes, func(a, b config.PlannedChange) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return changes, nil
+}
+
+// planComponent computes the changes to the objects of the given [component]
+// by comparing the rendered objects with the objects of its ManagedResource.
+// The differences of updated objects are logged.
+func (a *Actuator) planComponent(ctx context.Context, logger logr.Logger, c component, v componentValues) ([]config.PlannedChange, error) {
+	name := ManagedResourceName(c.name)
+	changes := make([]config.PlannedChange, 0)
+
+	current := make(map[string]client.Object)
+	mr := &resourcesv1alpha1.ManagedResource{}
+	err := a.client.Get(ctx, client.ObjectKey{Namespace: v.namespace, Name: name}, mr)
+	switch {
+	case apierrors.IsNotFound(err):
+		mr.Name = name
+		mr.Namespace = v.namespace
+		change, err := a.plannedChange(config.PlannedActionCreate, c.name, mr)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	case err != nil:
+		return nil, fmt.Errorf("failed to get managed resource %s: %w", name, err)
+	default:
+		objects, err := managedresources.GetObjects(ctx, a.client, v.namespace, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get objects of component %s: %w", c.name, err)
+		}
+		for _, obj := range objects {
+			key, err := a.objectKey(obj)
+			if err != nil {
+				return nil, err
+			}
+			current[key] = obj
+		}
+	}
+
+	objectChanges := make([]config.PlannedChange, 0)
+	for _, obj := range c.objects(v) {
+		key, err := a.objectKey(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, ok := current[key]
+		delete(current, key)
+		if !ok {
+			change, err := a.plannedChange(config.PlannedActionCreate, c.name, obj)
+			if err != nil {
+				return nil, err
+			}
+			objectChanges = append(objectChanges, change)
+
+			continue
+		}
+
+		before, err := comparableObject(existing)
+		if err != nil {
+			return nil, err
+		}
+		after, err := comparableObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		if equality.Semantic.DeepEqual(before, after) {
+			continue
+		}
+
+		change, err := a.plannedChange(config.PlannedActionUpdate, c.name, obj)
+		if err != nil {
+			return nil, err
+		}
+		objectChanges = append(objectChanges, change)
+		logger.Info("planned update", "component", c.name, "kind", change.Kind, "name", change.Name, "diff", diff.Diff(before, after))
+	}
+
+	// Objects which are no longer rendered would be removed
+	for _, obj := range current {
+		change, err := a.plannedChange(config.PlannedActionDelete, c.name, obj)
+		if err != nil {
+			return nil, err
+		}
+		objectChanges = append(objectChanges, change)
+	}
+
+	slices.SortFunc(objectChanges, func(a, b config.PlannedChange) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.Name, b.Name))
+	})
+
+	// The ManagedResource itself is updated, when any of its objects change
+	if len(objectChanges) > 0 && len(changes) == 0 {
+		change, err := a.plannedChange(config.PlannedActionUpdate, c.name, mr)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return append(changes, objectChanges...), nil
+}
+
+// plannedChange returns a [config.PlannedChange] with the given action for
+// the given object.
+func (a *Actuator) plannedChange(action config.PlannedAction, componentName string, obj client.Object) (config.PlannedChange, error) {
+	gvk, err := apiutil.GVKForObject(obj, a.client.Scheme())
+	if err != nil {
+		return config.PlannedChange{}, fmt.Errorf("failed to get kind of object %s: %w", obj.GetName(), err)
+	}
+
+	change := config.PlannedChange{
+		Action:    action,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Component: componentName,
+	}
+
+	return change, nil
+}
+
+// objectKey returns a key, which identifies the given object by its kind,
+// namespace and name.
+func (a *Actuator) objectKey(obj client.Object) (string, error) {
+	gvk, err := apiutil.GVKForObject(obj, a.client.Scheme())
+	if err != nil {
+		return "", fmt.Errorf("failed to get kind of object %s: %w", obj.GetName(), err)
+	}
+
+	return fmt.Sprintf("%s/%s/%s", gvk.GroupKind(), obj.GetNamespace(), obj.GetName()), nil
+}
+
+// comparableObject converts the given object into a form, which can be
+// compared with other objects of the same kind regardless of their type
+// information and status.
+func comparableObject(obj client.Object) (map[string]any, error) {
+	result, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert object %s: %w", obj.GetName(), err)
+	}
+
+	delete(result, "apiVersion")
+	delete(result, "kind")
+	delete(result, "status")
+
+	return result, nil
+}
+
+// recordPlan records the given [config.Plan] in the provider status of the
+// given [extensionsv1alpha1.Extension] resource and logs the planned changes.
+func (a *Actuator) recordPlan(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension, plan *config.Plan) error {
+	logger.Info("dry-run plan computed", "operation", plan.Operation, "changes", len(plan.Changes))
+	for _, change := range plan.Changes {
+		logger.Info(
+			"planned change",
+			"action", change.Action,
+			"kind", change.Kind,
+			"namespace", change.Namespace,
+			"name", change.Name,
+			"component", change.Component,
+		)
+	}
+
+	status, err := a.getStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(status.Plan, plan) {
+		return nil
+	}
+
+	status.Plan = plan
+
+	return a.updateStatus(ctx, ex, status)
+}
+
+// clearPlan removes a previously recorded dry-run plan from the provider
+// status of the given [extensionsv1alpha1.Extension] resource.
+func (a *Actuator) clearPlan(ctx context.Context, ex *extensionsv1alpha1.Extension) error {
+	status, err := a.getStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	if status.Plan == nil {
+		return nil
+	}
+
+	status.Plan = nil
+
+	return a.updateStatus(ctx, ex, status)
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"errors"
+	"time"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+const (
+	// configurationProblemRequeueInterval is the interval after which
+	// reconciliation is retried on a configuration problem. Configuration
+	// problems are not fixed by retrying, but a change of the provider config
+	// triggers a new reconciliation anyway, so the interval is rather long.
+	configurationProblemRequeueInterval = 10 * time.Minute
+
+	// dependencyRequeueInterval is the interval after which reconciliation
+	// is retried, when a dependency of the extension, e.g. the Cluster
+	// resource or a referenced secret, does not exist yet.
+	dependencyRequeueInterval = 30 * time.Second
+)
+
+// configurationProblem returns an error with the
+// [gardencorev1beta1.ErrorConfigurationProblem] code for the given error, which
+// is caused by a misconfiguration of the extension. Since retrying does not
+// help, reconciliation is requeued after a long interval, instead of being
+// retried with exponential backoff.
+func configurationProblem(err error) error {
+	return &reconcilerutils.RequeueAfterError{
+		Cause:        v1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorConfigurationProblem),
+		RequeueAfter: configurationProblemRequeueInterval,
+	}
+}
+
+// missingDependency returns an error with the
+// [gardencorev1beta1.ErrorRetryableInfraDependencies] code for the given error,
+// which is caused by a dependency of the extension, which does not exist yet.
+func missingDependency(err error) error {
+	return &reconcilerutils.RequeueAfterError{
+		Cause:        v1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorRetryableInfraDependencies),
+		RequeueAfter: dependencyRequeueInterval,
+	}
+}
+
+// classifyError classifies the given error returned by the [Actuator].
+// Transient errors of the API server, e.g. timeouts or throttling, are coded
+// with [gardencorev1beta1.ErrorRetryableInfraDependencies] and retried with
+// exponential backoff. Conflicts of server-side apply are treated as
+// configuration problems. Conflicts of the resource version are ordinary
+// optimistic concurrency failures, which are retried without an error code.
+// Errors, which already carry error codes, and any other errors are returned
+// as is.
+//
+// The extension reconciler honours the requeue hint of a
+// [reconcilerutils.RequeueAfterError] only if it is not wrapped, so it is
+// returned unwrapped.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var requeueErr *reconcilerutils.RequeueAfterError
+	if errors.As(err, &requeueErr) {
+		return requeueErr
+	}
+
+	if len(v1beta1helper.ExtractErrorCodes(err)) > 0 {
+		return err
+	}
+
+	// Conflicts with fields owned by other field managers are not resolved
+	// by retrying, but must be resolved by the other field managers.
+	if errors.Is(err, ErrApplyConflict) {
+		return configurationProblem(err)
+	}
+
+	if isTransientError(err) {
+		return v1beta1helper.NewErrorWithCodes(err, gardencorev1beta1.ErrorRetryableInfraDependencies)
+	}
+
+	return err
+}
+
+// isTransientError returns true, if the given error is a transient error of
+// the API server or of the connection to it.
+func isTransientError(err error) bool {
+	switch {
+	case apierrors.IsServerTimeout(err),
+		apierrors.IsTimeout(err),
+		apierrors.IsTooManyRequests(err),
+		apierrors.IsServiceUnavailable(err),
+		apierrors.IsInternalError(err):
+		return true
+	case utilnet.IsConnectionRefused(err),
+		utilnet.IsConnectionReset(err),
+		utilnet.IsProbableEOF(err):
+		return true
+	default:
+		return false
+	}
+}
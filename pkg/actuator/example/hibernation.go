@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	"github.com/gardener/gardener/pkg/utils/kubernetes/health"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"gardener-extension-example/pkg/apis/config"
+)
+
+// componentRequeueInterval is the interval after which reconciliation is
+// retried, while waiting for a component to become healthy during
+// hibernation or wake-up.
+const componentRequeueInterval = 10 * time.Second
+
+// reconcileComponents deploys the seed-side components and takes care of
+// scaling them down on hibernation and restoring them on wake-up. The
+// hibernation phase of the components is recorded in the provider status of
+// the given [extensionsv1alpha1.Extension] resource.
+func (a *Actuator) reconcileComponents(
+	ctx context.Context,
+	logger logr.Logger,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	cfg config.ExampleConfig,
+	generated generatedSecrets,
+) error {
+	status, err := a.getStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := a.getReferencedSecrets(ctx, ex.Namespace, cluster, cfg)
+	if err != nil {
+		return err
+	}
+
+	values := componentValues{
+		namespace: ex.Namespace,
+		config:    cfg,
+		image:     a.image,
+		replicas:  int32(extensionscontroller.GetReplicas(cluster, 1)),
+		secrets:   secrets,
+		generated: generated,
+	}
+
+	phase := status.HibernationPhase
+	switch {
+	case extensionscontroller.IsHibernationEnabled(cluster) && phase == config.HibernationPhaseHibernated:
+		// Already hibernated, make sure that the components stay
+		// scaled down.
+		return a.deployComponents(ctx, components, values)
+	case extensionscontroller.IsHibernationEnabled(cluster):
+		logger.Info("hibernating seed-side components", "phase", phase)
+		if err := a.setHibernationPhase(ctx, ex, status, config.HibernationPhaseHibernating); err != nil {
+			return err
+		}
+
+		// Scale down the components in reverse order
+		reversed := slices.Clone(components)
+		slices.Reverse(reversed)
+		if err := a.rollOutComponents(ctx, reversed, values); err != nil {
+			return err
+		}
+
+		logger.Info("seed-side components have been hibernated")
+
+		return a.setHibernationPhase(ctx, ex, status, config.HibernationPhaseHibernated)
+	case phase == config.HibernationPhaseHibernating || phase == config.HibernationPhaseHibernated || phase == config.HibernationPhaseWakingUp:
+		logger.Info("waking up seed-side components", "phase", phase)
+		if err := a.setHibernationPhase(ctx, ex, status, config.HibernationPhaseWakingUp); err != nil {
+			return err
+		}
+
+		if err := a.rollOutComponents(ctx, components, values); err != nil {
+			return err
+		}
+
+		logger.Info("seed-side components have been woken up")
+
+		return a.setHibernationPhase(ctx, ex, status, config.HibernationPhaseAwake)
+	default:
+		if err := a.deployComponents(ctx, components, values); err != nil {
+			return err
+		}
+
+		return a.setHibernationPhase(ctx, ex, status, config.HibernationPhaseAwake)
+	}
+}
+
+// deployComponents deploys the given components without waiting for them to
+// become healthy.
+func (a *Actuator) deployComponents(ctx context.Context, comps []component, values componentValues) error {
+	for _, c := range comps {
+		if err := a.deployComponent(ctx, c, values); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rollOutComponents deploys the given components one after another in the
+// given order. Each component must become healthy before the next one is
+// deployed. If a component is not healthy yet, a
+// [reconcilerutils.RequeueAfterError] is returned, so that the roll-out is
+// continued during the next reconciliation.
+func (a *Actuator) rollOutComponents(ctx context.Context, comps []component, values componentValues) error {
+	for _, c := range comps {
+		if err := a.deployComponent(ctx, c, values); err != nil {
+			return err
+		}
+
+		if err := a.checkComponent(ctx, c, values.namespace); err != nil {
+			return &reconcilerutils.RequeueAfterError{
+				Cause:        fmt.Errorf("waiting for component %s: %w", c.name, err),
+				RequeueAfter: componentRequeueInterval,
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkComponent returns an error, if the ManagedResource of the given
+// [component] is not healthy.
+func (a *Actuator) checkComponent(ctx context.Context, c component, namespace string) error {
+	mr := &resourcesv1alpha1.ManagedResource{}
+	key := client.ObjectKey{Namespace: namespace, Name: ManagedResourceName(c.name)}
+	if err := a.client.Get(ctx, key, mr); err != nil {
+		return fmt.Errorf("failed to get managed resource %s: %w", key, err)
+	}
+
+	return health.CheckManagedResource(mr)
+}
+
+// setHibernationPhase records the given [config.HibernationPhase] in the
+// provider status of the [extensionsv1alpha1.Extension] resource, if it differs
+// from the current one.
+func (a *Actuator) setHibernationPhase(
+	ctx context.Context,
+	ex *extensionsv1alpha1.Extension,
+	status *config.ExampleStatus,
+	phase config.HibernationPhase,
+) error {
+	if status.HibernationPhase == phase {
+		return nil
+	}
+
+	status.HibernationPhase = phase
+
+	return a.updateStatus(ctx, ex, status)
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	gardenerutils "github.com/gardener/gardener/pkg/utils/gardener"
+	"github.com/go-logr/logr"
+)
+
+// withClusterValues returns the given logger enriched with metadata about the
+// shoot from the given [extensionscontroller.Cluster], so that the logs of the
+// actuator can be correlated per shoot with the logs of Gardener.
+//
+// Gardener does not assign an ID to its operations, so the UID of the shoot
+// along with the type of its last operation and its generation identify the
+// operation instead.
+func withClusterValues(logger logr.Logger, cluster *extensionscontroller.Cluster) logr.Logger {
+	if cluster == nil || cluster.Shoot == nil {
+		return logger
+	}
+
+	shoot := cluster.Shoot
+	values := []any{
+		"shoot", shoot.Name,
+		"project", projectName(shoot),
+		"shootUID", shoot.Status.UID,
+		"shootGeneration", shoot.Generation,
+	}
+	if cluster.Seed != nil {
+		values = append(values, "seed", cluster.Seed.Name)
+	} else if shoot.Spec.SeedName != nil {
+		values = append(values, "seed", *shoot.Spec.SeedName)
+	}
+	if op := shoot.Status.LastOperation; op != nil {
+		values = append(values, "shootOperation", op.Type)
+	}
+
+	return logger.WithValues(values...)
+}
+
+// withExtensionValues returns the given logger enriched with the metadata
+// about the shoot of the given [extensionsv1alpha1.Extension] resource, if its
+// [extensionscontroller.Cluster] can be read, and with the generation of the
+// extension resource.
+func (a *Actuator) withExtensionValues(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) logr.Logger {
+	logger = logger.WithValues("generation", ex.Generation)
+
+	// The cluster may already be gone, e.g. when the extension resource
+	// is deleted, so the metadata is added on a best-effort basis.
+	cluster, err := extensionscontroller.GetCluster(ctx, a.client, ex.Namespace)
+	if err != nil {
+		return logger
+	}
+
+	return withClusterValues(logger, cluster)
+}
+
+// projectName returns the name of the project of the given shoot, which is
+// derived from its technical ID, or from its namespace, if the technical ID
+// is not known yet.
+func projectName(shoot *gardencorev1beta1.Shoot) string {
+	// The technical ID is of the form "shoot--<project>--<shoot>".
+	if rest, ok := strings.CutPrefix(shoot.Status.TechnicalID, v1beta1constants.TechnicalIDPrefix+"-"); ok {
+		if project, ok := strings.CutSuffix(rest, "--"+shoot.Name); ok {
+			return project
+		}
+	}
+
+	if shoot.Namespace == v1beta1constants.GardenNamespace {
+		return v1beta1constants.GardenNamespace
+	}
+
+	return strings.TrimPrefix(shoot.Namespace, gardenerutils.ProjectNamespacePrefix)
+}
@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"gardener-extension-example/pkg/apis/config"
+)
+
+const (
+	// AnnotationOperation is the annotation on the Extension or Shoot
+	// resource, which requests a custom operation of the [Actuator]. The
+	// annotation is removed from the Extension resource, once the operation
+	// has been completed.
+	AnnotationOperation = "example.extensions.gardener.cloud/operation"
+
+	// OperationRotateCredentials is a custom operation, which forces the
+	// rotation of the server certificate and the static token of the
+	// seed-side components.
+	OperationRotateCredentials = "rotate-credentials"
+
+	// OperationRecreateComponents is a custom operation, which deletes and
+	// re-creates the seed-side components.
+	OperationRecreateComponents = "recreate-components"
+
+	// OperationCollectDiagnostics is a custom operation, which collects
+	// diagnostics about the seed-side components and records them in the
+	// provider status.
+	OperationCollectDiagnostics = "collect-diagnostics"
+
+	// OperationSourceExtension means that a custom operation has been
+	// requested via annotation on the Extension resource.
+	OperationSourceExtension = "Extension"
+
+	// OperationSourceShoot means that a custom operation has been requested
+	// via annotation on the Shoot resource.
+	OperationSourceShoot = "Shoot"
+)
+
+// ErrUnknownOperation is an error, which is returned when an unknown custom
+// operation is requested.
+var ErrUnknownOperation = errors.New("unknown operation")
+
+// requestedOperation returns the custom operation requested via annotation on
+// the given [extensionsv1alpha1.Extension] resource or the Shoot of the given
+// cluster, and the kind of the resource, which requested it. The annotation on
+// the Extension resource takes precedence. Operations requested via the Shoot
+// are returned only, if they have not been performed already.
+func requestedOperation(ex *extensionsv1alpha1.Extension, cluster *extensionscontroller.Cluster, status *config.ExampleStatus) (string, string) {
+	if op := ex.Annotations[AnnotationOperation]; op != "" {
+		return op, OperationSourceExtension
+	}
+
+	if op := shootOperation(cluster); op != "" && op != status.ObservedShootOperation {
+		return op, OperationSourceShoot
+	}
+
+	return "", ""
+}
+
+// shootOperation returns the custom operation requested via annotation on the
+// Shoot of the given cluster, if any.
+func shootOperation(cluster *extensionscontroller.Cluster) string {
+	if cluster.Shoot == nil {
+		return ""
+	}
+
+	return cluster.Shoot.Annotations[AnnotationOperation]
+}
+
+// forgetShootOperation drops the custom operation requested via the Shoot of
+// the given cluster from the provider status, once the annotation has been
+// removed from the Shoot, so that the same operation can be requested again.
+func (a *Actuator) forgetShootOperation(
+	ctx context.Context,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	status *config.ExampleStatus,
+) error {
+	if status.ObservedShootOperation == "" || status.ObservedShootOperation == shootOperation(cluster) {
+		return nil
+	}
+
+	status.ObservedShootOperation = ""
+
+	return a.updateStatus(ctx, ex, status)
+}
+
+// reconcileOperation performs the given custom operation, followed by a full
+// reconciliation, if the operation requires it. The result is recorded in the
+// provider status of the given [extensionsv1alpha1.Extension] resource, and
+// the annotation is removed from it, regardless of whether the operation
+// succeeded, so that a failed operation is not repeated over and over again.
+// Unknown operations are recorded as failed and otherwise ignored.
+func (a *Actuator) reconcileOperation(
+	ctx context.Context,
+	logger logr.Logger,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	cfg config.ExampleConfig,
+	checksum string,
+	op string,
+	source string,
+) error {
+	logger = logger.WithValues("operation", op, "source", source)
+	logger.Info("performing custom operation")
+
+	var (
+		details []string
+		err     error
+	)
+
+	switch op {
+	case OperationRotateCredentials:
+		if err = a.rotateCredentials(ctx, ex.Namespace); err == nil {
+			err = a.fullReconcile(ctx, logger, ex, cluster, cfg, checksum)
+		}
+	case OperationRecreateComponents:
+		if err = a.deleteComponents(ctx, ex.Namespace); err == nil {
+			err = a.fullReconcile(ctx, logger, ex, cluster, cfg, checksum)
+		}
+	case OperationCollectDiagnostics:
+		details, err = a.collectDiagnostics(ctx, ex)
+	default:
+		err = fmt.Errorf("%w %q", ErrUnknownOperation, op)
+	}
+
+	if recordErr := a.recordOperation(ctx, ex, op, source, details, err); recordErr != nil {
+		return errors.Join(err, recordErr)
+	}
+
+	if err != nil {
+		logger.Info("custom operation failed", "reason", err.Error())
+		if errors.Is(err, ErrUnknownOperation) {
+			return nil
+		}
+
+		return err
+	}
+
+	logger.Info("custom operation completed")
+
+	return nil
+}
+
+// recordOperation records the result of the given custom operation in the
+// provider status of the given [extensionsv1alpha1.Extension] resource and
+// removes the annotation, which requested the operation, from it.
+func (a *Actuator) recordOperation(
+	ctx context.Context,
+	ex *extensionsv1alpha1.Extension,
+	op string,
+	source string,
+	details []string,
+	opErr error,
+) error {
+	status, err := a.getStatus(ex)
+	if err != nil {
+		return err
+	}
+
+	result := &config.CustomOperationResult{
+		Operation:      op,
+		Source:         source,
+		State:          config.CustomOperationStateSucceeded,
+		Description:    fmt.Sprintf("Operation %s has been completed successfully", op),
+		Details:        details,
+		CompletionTime: metav1.NewTime(a.clock.Now()),
+	}
+	if opErr != nil {
+		result.State = config.CustomOperationStateFailed
+		result.Description = fmt.Sprintf("Operation %s has failed: %s", op, opErr)
+	}
+
+	status.LastCustomOperation = result
+	if source == OperationSourceShoot {
+		status.ObservedShootOperation = op
+	}
+
+	if err := a.updateStatus(ctx, ex, status); err != nil {
+		return err
+	}
+
+	if source != OperationSourceExtension {
+		return nil
+	}
+
+	patch := client.MergeFrom(ex.DeepCopy())
+	delete(ex.Annotations, AnnotationOperation)
+	if err := a.client.Patch(ctx, ex, patch); err != nil {
+		return fmt.Errorf("failed to remove annotation %s: %w", AnnotationOperation, err)
+	}
+
+	return nil
+}
+
+// rotateCredentials deletes the server certificate and the static token
+// generated by the secrets manager of the [Actuator] from the given
+// namespace, so that new ones are generated by the subsequent full
+// reconciliation. The new secrets keep their names, so the workload is rolled
+// out via the checksum annotations of their data. The CA is rotated in
+// lockstep with the shoot only.
+//
+// TODO(user): adjust the secrets to whatever your extension deploys
+func (a *Actuator) rotateCredentials(ctx context.Context, namespace string) error {
+	for _, name := range []string{ServerSecretName, TokenSecretName} {
+		labels := managedSecretLabels()
+		labels[secretsmanager.LabelKeyName] = name
+
+		err := a.client.DeleteAllOf(
+			ctx,
+			&corev1.Secret{},
+			client.InNamespace(namespace),
+			client.MatchingLabels(labels),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete secret %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// collectDiagnostics returns diagnostics about the seed-side components of the
+// given [extensionsv1alpha1.Extension] resource, i.e. the hibernation phase and
+// the conditions of their ManagedResources.
+//
+// TODO(user): collect whatever helps troubleshooting your extension
+func (a *Actuator) collectDiagnostics(ctx context.Context, ex *extensionsv1alpha1.Extension) ([]string, error) {
+	status, err := a.getStatus(ex)
+	if err != nil {
+		return nil, err
+	}
+
+	details := []string{fmt.Sprintf("hibernation phase: %s", status.HibernationPhase)}
+	for _, c := range components {
+		mr := &resourcesv1alpha1.ManagedResource{}
+		key := client.ObjectKey{Namespace: ex.Namespace, Name: ManagedResourceName(c.name)}
+		if err := a.client.Get(ctx, key, mr); err != nil {
+			if apierrors.IsNotFound(err) {
+				details = append(details, fmt.Sprintf("component %s: managed resource not found", c.name))
+
+				continue
+			}
+
+			return nil, fmt.Errorf("failed to get managed resource %s: %w", key, err)
+		}
+
+		for _, conditionType := range []gardencorev1beta1.ConditionType{
+			resourcesv1alpha1.ResourcesApplied,
+			resourcesv1alpha1.ResourcesHealthy,
+			resourcesv1alpha1.ResourcesProgressing,
+		} {
+			condition := v1beta1helper.GetCondition(mr.Status.Conditions, conditionType)
+			if condition == nil {
+				details = append(details, fmt.Sprintf("component %s: %s unknown", c.name, conditionType))
+
+				continue
+			}
+
+			details = append(details, fmt.Sprintf("component %s: %s=%s (%s) %s", c.name, conditionType, condition.Status, condition.Reason, condition.Message))
+		}
+	}
+
+	return details, nil
+}
@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	"github.com/gardener/gardener/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"gardener-extension-example/pkg/apis/config"
+)
+
+// secretsMountPath is the path at which referenced secrets are mounted in the
+// seed-side workload. Each secret is mounted in a sub-directory named after
+// the resource reference.
+const secretsMountPath = "/etc/example/secrets"
+
+// secretVolumePrefix is the prefix of the volumes of the referenced secrets
+// in the seed-side workload.
+const secretVolumePrefix = "secret-"
+
+// referencedSecret is a secret referenced by the [config.ExampleConfig], which
+// has been copied by gardenlet into the shoot namespace.
+type referencedSecret struct {
+	// name is the name of the resource reference in the shoot spec.
+	name string
+
+	// secretName is the name of the copied secret in the shoot namespace.
+	secretName string
+
+	// volumeName is the name of the volume of the secret in the seed-side
+	// workload, see [secretVolumeName].
+	volumeName string
+
+	// checksum is the checksum of the secret data.
+	checksum string
+}
+
+// getReferencedSecrets resolves the secrets referenced by the given
+// [config.ExampleConfig] from the resources of the shoot. The referenced
+// secrets are copied by gardenlet into the shoot namespace with a "ref-" name
+// prefix.
+func (a *Actuator) getReferencedSecrets(
+	ctx context.Context,
+	namespace string,
+	cluster *extensionscontroller.Cluster,
+	cfg config.ExampleConfig,
+) ([]referencedSecret, error) {
+	if len(cfg.Spec.SecretRefs) == 0 {
+		return nil, nil
+	}
+
+	if cluster.Shoot == nil {
+		return nil, missingDependency(fmt.Errorf("no shoot found in cluster %s", cluster.ObjectMeta.Name))
+	}
+
+	result := make([]referencedSecret, 0, len(cfg.Spec.SecretRefs))
+	for _, ref := range cfg.Spec.SecretRefs {
+		resource := v1beta1helper.GetResourceByName(cluster.Shoot.Spec.Resources, ref.Name)
+		if resource == nil {
+			return nil, configurationProblem(fmt.Errorf("secret reference %s not found in shoot resources", ref.Name))
+		}
+
+		if resource.ResourceRef.APIVersion != "v1" || resource.ResourceRef.Kind != "Secret" {
+			return nil, configurationProblem(fmt.Errorf("resource reference %s is not a secret", ref.Name))
+		}
+
+		secret := &corev1.Secret{}
+		if err := extensionscontroller.GetObjectByReference(ctx, a.client, &resource.ResourceRef, namespace, secret); err != nil {
+			err = fmt.Errorf("failed to get referenced secret %s: %w", ref.Name, err)
+			if apierrors.IsNotFound(err) {
+				// The referenced secret is copied into the shoot
+				// namespace by gardenlet, which may not have
+				// happened yet.
+				return nil, missingDependency(err)
+			}
+
+			return nil, err
+		}
+
+		item := referencedSecret{
+			name:       ref.Name,
+			secretName: secret.Name,
+			volumeName: secretVolumeName(ref.Name),
+			checksum:   utils.ComputeSecretChecksum(secret.Data),
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// secretVolumeName returns the name of the volume for the referenced secret
+// with the given name, which is used for its checksum annotation as well.
+//
+// Resource references may have names of up to 253 characters, whereas the
+// names of volumes must be DNS labels of at most 63 characters. Names, which
+// do not fit, are truncated and suffixed with a hash of the full name, so that
+// they remain unique.
+func secretVolumeName(name string) string {
+	volumeName := secretVolumePrefix + name
+	if len(validation.IsDNS1123Label(volumeName)) == 0 {
+		return volumeName
+	}
+
+	hash := utils.ComputeSHA256Hex([]byte(name))[:8]
+	sanitized := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+
+		return '-'
+	}, strings.ToLower(name))
+
+	maxLen := validation.DNS1123LabelMaxLength - len(secretVolumePrefix) - len(hash) - 1
+	if len(sanitized) > maxLen {
+		sanitized = sanitized[:maxLen]
+	}
+
+	return secretVolumePrefix + strings.TrimRight(sanitized, "-") + "-" + hash
+}
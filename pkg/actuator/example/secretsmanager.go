@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	extensionssecretsmanager "github.com/gardener/gardener/extensions/pkg/util/secret/manager"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils"
+	kutil "github.com/gardener/gardener/pkg/utils/kubernetes"
+	secretsutils "github.com/gardener/gardener/pkg/utils/secrets"
+	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SecretsManagerIdentity is the identity of the secrets manager used by
+	// the [Actuator].
+	SecretsManagerIdentity = "extension-example"
+
+	// CASecretName is the name of the CA secret generated by the [Actuator].
+	CASecretName = "ca-extension-example"
+	// ServerSecretName is the name of the server certificate secret
+	// generated by the [Actuator].
+	ServerSecretName = "extension-example-server"
+	// TokenSecretName is the name of the static token secret generated by
+	// the [Actuator].
+	TokenSecretName = "extension-example-token"
+)
+
+// generatedSecrets provides the names of the secrets generated by the secrets
+// manager of the [Actuator] along with the checksums of their data.
+type generatedSecrets struct {
+	caBundle string
+	server   string
+	token    string
+
+	// checksums are the checksums of the data of the generated secrets,
+	// keyed by the names of their configs, e.g. [ServerSecretName].
+	checksums map[string]string
+}
+
+// secretConfigs returns the configs of the secrets generated by the [Actuator]
+// for the given namespace.
+//
+// TODO(user): adjust the secrets to whatever your extension deploys
+func secretConfigs(namespace string) []extensionssecretsmanager.SecretConfigWithOptions {
+	return []extensionssecretsmanager.SecretConfigWithOptions{
+		{
+			Config: &secretsutils.CertificateSecretConfig{
+				Name:       CASecretName,
+				CommonName: CASecretName,
+				CertType:   secretsutils.CACert,
+			},
+			Options: []secretsmanager.GenerateOption{secretsmanager.Persist()},
+		},
+		{
+			Config: &secretsutils.CertificateSecretConfig{
+				Name:                        ServerSecretName,
+				CommonName:                  deploymentName,
+				DNSNames:                    kutil.DNSNamesForService(deploymentName, namespace),
+				CertType:                    secretsutils.ServerCert,
+				SkipPublishingCACertificate: true,
+			},
+			Options: []secretsmanager.GenerateOption{secretsmanager.SignedByCA(CASecretName, secretsmanager.UseCurrentCA)},
+		},
+		{
+			Config: &secretsutils.StaticTokenSecretConfig{
+				Name: TokenSecretName,
+				Tokens: map[string]secretsutils.TokenConfig{
+					deploymentName: {
+						Username: deploymentName,
+						UserID:   deploymentName,
+					},
+				},
+			},
+			Options: []secretsmanager.GenerateOption{secretsmanager.Persist()},
+		},
+	}
+}
+
+// newSecretsManager returns a new [secretsmanager.Interface] for the shoot
+// namespace of the given cluster, which uses the given [client.Client]. CAs
+// are rotated in lockstep with the CA rotation of the shoot, i.e. new CAs are
+// generated during the Preparing phase and old CAs are dropped during the
+// Completing phase.
+func (a *Actuator) newSecretsManager(
+	ctx context.Context,
+	logger logr.Logger,
+	c client.Client,
+	cluster *extensionscontroller.Cluster,
+) (secretsmanager.Interface, error) {
+	if cluster.Shoot == nil {
+		return nil, fmt.Errorf("no shoot found in cluster %s", cluster.ObjectMeta.Name)
+	}
+
+	sm, err := extensionssecretsmanager.SecretsManagerForCluster(
+		ctx,
+		logger.WithName("secretsmanager"),
+		a.clock,
+		c,
+		cluster,
+		SecretsManagerIdentity,
+		secretConfigs(cluster.ObjectMeta.Name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secrets manager: %w", err)
+	}
+
+	return sm, nil
+}
+
+// generateSecrets generates the secrets for the seed-side components and
+// persists them in the state of the given [extensionsv1alpha1.Extension]
+// resource.
+func (a *Actuator) generateSecrets(
+	ctx context.Context,
+	logger logr.Logger,
+	ex *extensionsv1alpha1.Extension,
+	cluster *extensionscontroller.Cluster,
+	sm secretsmanager.Interface,
+) (generatedSecrets, error) {
+	if phase := v1beta1helper.GetShootCARotationPhase(cluster.Shoot.Status.Credentials); phase != "" {
+		logger.Info("generating secrets", "caRotationPhase", phase)
+	}
+
+	result, err := generateAllSecrets(ctx, sm, ex.Namespace)
+	if err != nil {
+		return generatedSecrets{}, err
+	}
+
+	if err := a.persistState(ctx, ex); err != nil {
+		return generatedSecrets{}, err
+	}
+
+	return result, nil
+}
+
+// generateAllSecrets generates the secrets for the given namespace with the
+// given [secretsmanager.Interface] and returns their names.
+func generateAllSecrets(ctx context.Context, sm secretsmanager.Interface, namespace string) (generatedSecrets, error) {
+	secrets, err := extensionssecretsmanager.GenerateAllSecrets(ctx, sm, secretConfigs(namespace))
+	if err != nil {
+		return generatedSecrets{}, fmt.Errorf("failed to generate secrets: %w", err)
+	}
+
+	caBundle, found := sm.Get(CASecretName)
+	if !found {
+		return generatedSecrets{}, fmt.Errorf("secret %s not found", CASecretName)
+	}
+
+	result := generatedSecrets{
+		caBundle: caBundle.Name,
+		server:   secrets[ServerSecretName].Name,
+		token:    secrets[TokenSecretName].Name,
+		checksums: map[string]string{
+			CASecretName:     utils.ComputeSecretChecksum(caBundle.Data),
+			ServerSecretName: utils.ComputeSecretChecksum(secrets[ServerSecretName].Data),
+			TokenSecretName:  utils.ComputeSecretChecksum(secrets[TokenSecretName].Data),
+		},
+	}
+
+	return result, nil
+}
+
+// cleanupSecrets removes secrets, which are no longer needed, e.g. old CAs
+// after the CA rotation of the shoot has been completed, and updates the state
+// of the given [extensionsv1alpha1.Extension] resource.
+func (a *Actuator) cleanupSecrets(ctx context.Context, ex *extensionsv1alpha1.Extension, sm secretsmanager.Interface) error {
+	if err := sm.Cleanup(ctx); err != nil {
+		return fmt.Errorf("failed to cleanup secrets: %w", err)
+	}
+
+	return a.persistState(ctx, ex)
+}
+
+// deleteSecrets deletes all secrets generated by the secrets manager of the
+// [Actuator] from the given namespace.
+func (a *Actuator) deleteSecrets(ctx context.Context, namespace string) error {
+	err := a.client.DeleteAllOf(
+		ctx,
+		&corev1.Secret{},
+		client.InNamespace(namespace),
+		client.MatchingLabels(managedSecretLabels()),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete secrets: %w", err)
+	}
+
+	return nil
+}
+
+// managedSecretLabels returns the labels of the secrets generated by the
+// secrets manager of the [Actuator].
+func managedSecretLabels() map[string]string {
+	return map[string]string{
+		secretsmanager.LabelKeyManagedBy:       secretsmanager.LabelValueSecretsManager,
+		secretsmanager.LabelKeyManagerIdentity: SecretsManagerIdentity,
+	}
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package example
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"gardener-extension-example/pkg/apis/config"
+	"gardener-extension-example/pkg/apis/config/v1alpha1"
+)
+
+// getStatus decodes and returns the provider status of the given
+// [extensionsv1alpha1.Extension] resource. An empty status is returned, if the
+// resource does not have a provider status yet.
+func (a *Actuator) getStatus(ex *extensionsv1alpha1.Extension) (*config.ExampleStatus, error) {
+	status := &config.ExampleStatus{}
+	if ex.Status.ProviderStatus == nil || len(ex.Status.ProviderStatus.Raw) == 0 {
+		return status, nil
+	}
+
+	if err := runtime.DecodeInto(a.decoder, ex.Status.ProviderStatus.Raw, status); err != nil {
+		return nil, fmt.Errorf("invalid provider status: %w", err)
+	}
+
+	return status, nil
+}
+
+// updateStatus updates the provider status of the given
+// [extensionsv1alpha1.Extension] resource with the given [config.ExampleStatus].
+func (a *Actuator) updateStatus(ctx context.Context, ex *extensionsv1alpha1.Extension, status *config.ExampleStatus) error {
+	providerStatus := &v1alpha1.ExampleStatus{}
+	if err := a.client.Scheme().Convert(status, providerStatus, nil); err != nil {
+		return fmt.Errorf("failed to convert provider status: %w", err)
+	}
+	providerStatus.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("ExampleStatus"))
+
+	patch := client.MergeFrom(ex.DeepCopy())
+	ex.Status.ProviderStatus = &runtime.RawExtension{Object: providerStatus}
+	if err := a.client.Status().Patch(ctx, ex, patch); err != nil {
+		return fmt.Errorf("failed to update provider status: %w", err)
+	}
+
+	return nil
+}
+
+// getState decodes and returns the state of the given
+// [extensionsv1alpha1.Extension] resource. An empty state is returned, if the
+// resource does not have a state yet.
+func (a *Actuator) getState(ex *extensionsv1alpha1.Extension) (*config.ExampleState, error) {
+	state := &config.ExampleState{}
+	if ex.Status.State == nil || len(ex.Status.State.Raw) == 0 {
+		return state, nil
+	}
+
+	if err := runtime.DecodeInto(a.decoder, ex.Status.State.Raw, state); err != nil {
+		return nil, fmt.Errorf("invalid extension state: %w", err)
+	}
+
+	return state, nil
+}
+
+// persistState records the secrets generated by the secrets manager of the
+// [Actuator], which must be persisted, in the state of the given
+// [extensionsv1alpha1.Extension] resource.
+func (a *Actuator) persistState(ctx context.Context, ex *extensionsv1alpha1.Extension) error {
+	current, err := a.getState(ex)
+	if err != nil {
+		return err
+	}
+
+	var items corev1.SecretList
+	labels := managedSecretLabels()
+	labels[secretsmanager.LabelKeyPersist] = secretsmanager.LabelValueTrue
+	listOpts := []client.ListOption{
+		client.InNamespace(ex.Namespace),
+		client.MatchingLabels(labels),
+	}
+	if err := a.client.List(ctx, &items, listOpts...); err != nil {
+		return fmt.Errorf("failed to list secrets to persist: %w", err)
+	}
+
+	desired := &config.ExampleState{}
+	for _, secret := range items.Items {
+		item := config.PersistedSecret{
+			Name:   secret.Name,
+			Labels: secret.Labels,
+			Type:   secret.Type,
+			Data:   secret.Data,
+		}
+		desired.Secrets = append(desired.Secrets, item)
+	}
+	slices.SortFunc(desired.Secrets, func(a, b config.PersistedSecret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	if equality.Semantic.DeepEqual(current.Secrets, desired.Secrets) {
+		return nil
+	}
+
+	state := &v1alpha1.ExampleState{}
+	if err := a.client.Scheme().Convert(desired, state, nil); err != nil {
+		return fmt.Errorf("failed to convert extension state: %w", err)
+	}
+	state.SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind("ExampleState"))
+
+	patch := client.MergeFrom(ex.DeepCopy())
+	ex.Status.State = &runtime.RawExtension{Object: state}
+	if err := a.client.Status().Patch(ctx, ex, patch); err != nil {
+		return fmt.Errorf("failed to update extension state: %w", err)
+	}
+
+	return nil
+}
+
+// restoreState re-creates the secrets persisted in the state of the given
+// [extensionsv1alpha1.Extension] resource, so that the secrets manager picks
+// them up instead of generating new ones after a control plane migration.
+func (a *Actuator) restoreState(ctx context.Context, ex *extensionsv1alpha1.Extension) error {
+	state, err := a.getState(ex)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range state.Secrets {
+		objectMeta := metav1.ObjectMeta{
+			Name:      item.Name,
+			Namespace: ex.Namespace,
+			Labels:    item.Labels,
+		}
+		secret := secretsmanager.Secret(objectMeta, item.Data)
+		if item.Type != "" {
+			secret.Type = item.Type
+		}
+		if err := a.client.Create(ctx, secret); client.IgnoreAlreadyExists(err) != nil {
+			return fmt.Errorf("failed to restore secret %s: %w", item.Name, err)
+		}
+	}
+
+	return nil
+}
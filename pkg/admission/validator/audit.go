@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"gardener-extension-example/pkg/metrics"
+)
+
+const (
+	// resourceShoot is the resource of an admission request for a Shoot
+	// in the garden cluster.
+	resourceShoot = "shoot"
+
+	// resourceExtension is the resource of an admission request for an
+	// Extension in the seed cluster.
+	resourceExtension = "extension"
+
+	// resultAllowed is the result of an admission request, which passed
+	// the validation.
+	resultAllowed = "allowed"
+
+	// resultDenied is the result of an admission request, which failed
+	// the validation.
+	resultDenied = "denied"
+)
+
+// The reasons below categorize why an admission request has been denied. They
+// are used as the reason label of the admission metrics and in the audit log,
+// so they must remain stable and of low cardinality.
+const (
+	reasonNone                  = "none"
+	reasonMissingProviderConfig = "missing_provider_config"
+	reasonInvalidProviderConfig = "invalid_provider_config"
+	reasonInvalidConfiguration  = "invalid_configuration"
+	reasonInvalidSecretRefs     = "invalid_secret_refs"
+	reasonOther                 = "other"
+)
+
+// rejection is an error, which is returned when the extension configuration
+// of a shoot is rejected, and which carries the category of the rejection.
+type rejection struct {
+	reason string
+	err    error
+}
+
+var _ error = &rejection{}
+
+// Error implements the [error] interface.
+func (r *rejection) Error() string {
+	return r.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (r *rejection) Unwrap() error {
+	return r.err
+}
+
+// rejectionReason returns the category of the given validation error, which
+// is [reasonNone] for a nil error.
+func rejectionReason(err error) string {
+	if err == nil {
+		return reasonNone
+	}
+
+	var r *rejection
+	if errors.As(err, &r) {
+		return r.reason
+	}
+
+	return reasonOther
+}
+
+// fieldErrors returns the messages of the individual field errors of the
+// given validation error, or its message, if it does not aggregate any field
+// errors.
+func fieldErrors(err error) []string {
+	var agg utilerrors.Aggregate
+	if !errors.As(err, &agg) {
+		return []string{err.Error()}
+	}
+
+	result := make([]string, 0, len(agg.Errors()))
+	for _, e := range agg.Errors() {
+		result = append(result, e.Error())
+	}
+
+	return result
+}
+
+// admit runs the given validation for an admission request for the given
+// resource with the given operation, and records its result in the admission
+// metrics and each denial in the audit log.
+func admit(ctx context.Context, resource string, operation admissionv1.Operation, validate func() error) error {
+	start := time.Now()
+	err := validate()
+	observeAdmission(resource, operation, err, time.Since(start))
+
+	// The logger carries the UID of the admission request, the user, who
+	// sent it, and the shoot and project it refers to, see
+	// [LogConstructor].
+	logger := logf.FromContext(ctx)
+	if err != nil {
+		logger.WithName("audit").Info(
+			"rejecting extension configuration",
+			"reason", rejectionReason(err),
+			"fieldErrors", fieldErrors(err),
+		)
+
+		return err
+	}
+	logger.V(1).Info("accepted extension configuration")
+
+	return nil
+}
+
+// observeAdmission records the result and duration of the validation of an
+// admission request for the given resource with the given operation in the
+// admission metrics.
+func observeAdmission(resource string, operation admissionv1.Operation, err error, duration time.Duration) {
+	result := resultAllowed
+	if err != nil {
+		result = resultDenied
+	}
+
+	metrics.AdmissionRequestsTotal.WithLabelValues(resource, string(operation), result, rejectionReason(err)).Inc()
+	metrics.AdmissionDurationSeconds.WithLabelValues(resource, string(operation), result).Observe(duration.Seconds())
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	"github.com/gardener/gardener/pkg/apis/core"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	exampleactuator "gardener-extension-example/pkg/actuator/example"
+)
+
+// extensionValidator is an implementation of [extensionswebhook.Validator],
+// which validates the provider configuration of the
+// [extensionsv1alpha1.Extension] resources in the seed cluster.
+type extensionValidator struct {
+	decoder       runtime.Decoder
+	reader        client.Reader
+	extensionType string
+}
+
+var _ extensionswebhook.Validator = &extensionValidator{}
+
+// NewExtensionValidator returns a new [extensionswebhook.Validator] for
+// [extensionsv1alpha1.Extension] objects, which enforces the same rules as
+// the validator for [core.Shoot] objects. The secrets referenced by the
+// provider config are validated against the resources of the shoot from the
+// [extensionscontroller.Cluster], which is read with the given
+// [client.Reader].
+func NewExtensionValidator(decoder runtime.Decoder, reader client.Reader) (extensionswebhook.Validator, error) {
+	validator := &extensionValidator{
+		decoder:       decoder,
+		reader:        reader,
+		extensionType: exampleactuator.ExtensionType,
+	}
+
+	if decoder == nil {
+		return nil, fmt.Errorf("invalid decoder specified for extension validator %s", validator.extensionType)
+	}
+	if reader == nil {
+		return nil, fmt.Errorf("invalid reader specified for extension validator %s", validator.extensionType)
+	}
+
+	return validator, nil
+}
+
+// Validate implements the [extensionswebhook.Validator] interface.
+func (v *extensionValidator) Validate(ctx context.Context, newObj, oldObj client.Object) error {
+	newEx, ok := newObj.(*extensionsv1alpha1.Extension)
+	if !ok {
+		return fmt.Errorf("invalid object type: %T", newObj)
+	}
+	oldEx, ok := oldObj.(*extensionsv1alpha1.Extension)
+	if !ok {
+		oldEx = nil
+	}
+
+	if newEx.DeletionTimestamp != nil || newEx.Spec.Type != v.extensionType {
+		return nil
+	}
+
+	// The controller and gardenlet keep updating the annotations and
+	// finalizers of existing extension resources, which must not be
+	// blocked by a configuration, which has been accepted before.
+	if oldEx != nil && equality.Semantic.DeepEqual(oldEx.Spec.ProviderConfig, newEx.Spec.ProviderConfig) {
+		return nil
+	}
+
+	operation := admissionv1.Create
+	if oldEx != nil {
+		operation = admissionv1.Update
+	}
+
+	return admit(ctx, resourceExtension, operation, func() error {
+		return v.validateExtension(ctx, newEx)
+	})
+}
+
+// validateExtension validates the extension configuration from the given
+// [extensionsv1alpha1.Extension] resource.
+func (v *extensionValidator) validateExtension(ctx context.Context, ex *extensionsv1alpha1.Extension) error {
+	path := field.NewPath("spec", "providerConfig")
+	cfg, err := validateProviderConfig(ctx, v.decoder, v.extensionType, path, ex.Spec.ProviderConfig)
+	if err != nil {
+		return err
+	}
+
+	// The Cluster resource is created by gardenlet before the extension
+	// resources. If it is missing anyway, the referenced secrets are
+	// validated by the actuator instead.
+	cluster, err := extensionscontroller.GetCluster(ctx, v.reader, ex.Namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get cluster for %s: %w", ex.Namespace, err)
+	}
+	if cluster.Shoot == nil {
+		return nil
+	}
+
+	return validateSecretRefs(v.extensionType, cfg, namedResources(cluster.Shoot.Spec.Resources))
+}
+
+// namedResources converts the given resource references of a versioned shoot
+// to the ones of a [core.Shoot].
+func namedResources(resources []gardencorev1beta1.NamedResourceReference) []core.NamedResourceReference {
+	result := make([]core.NamedResourceReference, 0, len(resources))
+	for _, r := range resources {
+		result = append(result, core.NamedResourceReference{
+			Name:        r.Name,
+			ResourceRef: r.ResourceRef,
+		})
+	}
+
+	return result
+}
+
+// NewExtensionValidatorWebhook returns a new validating
+// [extensionswebhook.Webhook] for the [extensionsv1alpha1.Extension] objects
+// in the seed cluster, which is served by the controller.
+func NewExtensionValidatorWebhook(mgr manager.Manager) (*extensionswebhook.Webhook, error) {
+	decoder := serializer.NewCodecFactory(mgr.GetScheme(), serializer.EnableStrict).UniversalDecoder()
+	validator, err := NewExtensionValidator(decoder, mgr.GetClient())
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("extension-validator.%s", exampleactuator.ExtensionType)
+	path := fmt.Sprintf("/webhooks/validate/extensions/%s", exampleactuator.ExtensionType)
+
+	// Only the shoot namespaces, which have the extension enabled, are
+	// selected, so that the extension resources of other types are not
+	// affected by the availability of the webhook.
+	namespaceSelector := extensionswebhook.BuildExtensionTypeNamespaceSelector(
+		exampleactuator.ExtensionType,
+		[]extensionsv1alpha1.ExtensionClass{extensionsv1alpha1.ExtensionClassShoot},
+	)
+
+	logger := mgr.GetLogger()
+	logger.Info("setting up webhook", "name", name, "path", path)
+
+	args := extensionswebhook.Args{
+		Name: name,
+		Path: path,
+		Validators: map[extensionswebhook.Validator][]extensionswebhook.Type{
+			validator: {{Obj: &extensionsv1alpha1.Extension{}}},
+		},
+		Target:            extensionswebhook.TargetSeed,
+		NamespaceSelector: namespaceSelector,
+	}
+
+	wh, err := extensionswebhook.New(mgr, args)
+	if err != nil {
+		return nil, err
+	}
+	wh.Webhook.LogConstructor = LogConstructor
+	wh.Webhook.Handler = NewWarningHandler(wh.Webhook.Handler)
+
+	// The extension resources are created and updated by gardenlet during
+	// each shoot reconciliation, which must not be blocked while the
+	// controller is unavailable. The shoots are still validated in the
+	// garden cluster.
+	wh.FailurePolicy = new(admissionregistrationv1.Ignore)
+
+	return wh, nil
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator_test
+
+import (
+	"context"
+	"encoding/json"
+
+	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	exampleactuator "gardener-extension-example/pkg/actuator/example"
+	"gardener-extension-example/pkg/admission/validator"
+	configinstall "gardener-extension-example/pkg/apis/config/install"
+)
+
+var _ = Describe("Extension Validator", func() {
+	const namespace = "shoot--local--local"
+
+	var (
+		ctx                = context.Background()
+		decoder            runtime.Decoder
+		fakeClient         client.Client
+		extensionValidator extensionswebhook.Validator
+		ex                 *extensionsv1alpha1.Extension
+	)
+
+	// providerConfig returns the raw provider config with the given spec.
+	providerConfig := func(spec string) *runtime.RawExtension {
+		return &runtime.RawExtension{
+			Raw: []byte(`{"apiVersion":"example.extensions.gardener.cloud/v1alpha1","kind":"ExampleConfig","spec":` + spec + `}`),
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		configinstall.Install(scheme)
+		decoder = serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder()
+
+		shoot := &gardencorev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "local",
+				Namespace: "garden-local",
+			},
+			Spec: gardencorev1beta1.ShootSpec{
+				Resources: []gardencorev1beta1.NamedResourceReference{
+					{
+						Name: "api-token",
+						ResourceRef: autoscalingv1.CrossVersionObjectReference{
+							APIVersion: "v1",
+							Kind:       "Secret",
+							Name:       "my-api-token",
+						},
+					},
+				},
+			},
+		}
+		shootData, err := json.Marshal(shoot)
+		Expect(err).NotTo(HaveOccurred())
+
+		cluster := &extensionsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+			Spec: extensionsv1alpha1.ClusterSpec{
+				CloudProfile: runtime.RawExtension{Raw: []byte(`{}`)},
+				Seed:         runtime.RawExtension{Raw: []byte(`{}`)},
+				Shoot:        runtime.RawExtension{Raw: shootData},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithObjects(cluster).Build()
+
+		extensionValidator, err = validator.NewExtensionValidator(decoder, fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		ex = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: namespace,
+			},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type:           exampleactuator.ExtensionType,
+					ProviderConfig: providerConfig(`{"foo":"bar","secretRefs":[{"name":"api-token"}]}`),
+				},
+			},
+		}
+	})
+
+	It("should fail to create extension validator without decoder or reader", func() {
+		_, err := validator.NewExtensionValidator(nil, fakeClient)
+		Expect(err).To(MatchError(ContainSubstring("invalid decoder specified")))
+
+		_, err = validator.NewExtensionValidator(decoder, nil)
+		Expect(err).To(MatchError(ContainSubstring("invalid reader specified")))
+	})
+
+	It("should successfully validate provider config", func() {
+		allowedCreates := admissionRequests("extension", "CREATE", "allowed", "none")
+		shootCreates := admissionRequests("shoot", "CREATE", "allowed", "none")
+
+		Expect(extensionValidator.Validate(ctx, ex, nil)).To(Succeed())
+		Expect(admissionRequests("extension", "CREATE", "allowed", "none")).To(Equal(allowedCreates + 1))
+		Expect(admissionRequests("shoot", "CREATE", "allowed", "none")).To(Equal(shootCreates))
+	})
+
+	It("should enforce the same rules as the shoot validator", func() {
+		ex.Spec.ProviderConfig = nil
+		Expect(extensionValidator.Validate(ctx, ex, nil)).To(MatchError(ContainSubstring("no provider config specified")))
+
+		ex.Spec.ProviderConfig = providerConfig(`{"foo":""}`)
+		Expect(extensionValidator.Validate(ctx, ex, nil)).To(MatchError(ContainSubstring("spec.foo: Required value")))
+
+		ex.Spec.ProviderConfig = providerConfig(`{"foo":"bar","unknown":true}`)
+		Expect(extensionValidator.Validate(ctx, ex, nil)).To(MatchError(ContainSubstring("invalid provider spec configuration")))
+	})
+
+	It("should validate secret references against the resources of the shoot", func() {
+		ex.Spec.ProviderConfig = providerConfig(`{"foo":"bar","secretRefs":[{"name":"api-token"},{"name":"missing"}]}`)
+		err := extensionValidator.Validate(ctx, ex, nil)
+		Expect(err).To(MatchError(ContainSubstring(`spec.secretRefs[1].name: Not found: "missing"`)))
+		Expect(err).NotTo(MatchError(ContainSubstring("secretRefs[0]")))
+
+		// Without a cluster the secret references are left to the actuator
+		ex.Namespace = "shoot--local--other"
+		Expect(extensionValidator.Validate(ctx, ex, nil)).To(Succeed())
+	})
+
+	It("should skip extensions of other types, deleted extensions and unchanged configurations", func() {
+		ex.Spec.ProviderConfig = providerConfig(`{"foo":""}`)
+
+		other := ex.DeepCopy()
+		other.Spec.Type = "other"
+		Expect(extensionValidator.Validate(ctx, other, nil)).To(Succeed())
+
+		deleted := ex.DeepCopy()
+		deleted.DeletionTimestamp = new(metav1.Now())
+		Expect(extensionValidator.Validate(ctx, deleted, nil)).To(Succeed())
+
+		updated := ex.DeepCopy()
+		updated.Annotations = map[string]string{"gardener.cloud/operation": "reconcile"}
+		Expect(extensionValidator.Validate(ctx, updated, ex)).To(Succeed())
+		Expect(extensionValidator.Validate(ctx, ex, nil)).NotTo(Succeed())
+	})
+
+	It("should derive shoot and project from the shoot namespace of extension requests", func() {
+		var lines []string
+		logger := funcr.New(func(prefix, args string) {
+			lines = append(lines, args)
+		}, funcr.Options{})
+		req := &admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Kind: metav1.GroupVersionKind{
+					Group:   extensionsv1alpha1.SchemeGroupVersion.Group,
+					Version: extensionsv1alpha1.SchemeGroupVersion.Version,
+					Kind:    "Extension",
+				},
+				Name:      ex.Name,
+				Namespace: ex.Namespace,
+				Operation: admissionv1.Create,
+			},
+		}
+		validator.LogConstructor(logger, req).Info("test")
+
+		Expect(lines).To(ConsistOf(SatisfyAll(
+			ContainSubstring(`"shoot"="local"`),
+			ContainSubstring(`"project"="local"`),
+			ContainSubstring(`"extension"="example"`),
+		)))
+	})
+})
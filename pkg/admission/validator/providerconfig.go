@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gardener/gardener/pkg/apis/core"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"gardener-extension-example/pkg/apis/config"
+	"gardener-extension-example/pkg/apis/config/validation"
+)
+
+// validateProviderConfig decodes and validates the given provider config of
+// the extension with the given type. The validation is shared by the
+// validators of all entry points, e.g. the [core.Shoot] in the garden and the
+// Extension in the seed, so that all of them enforce the same rules.
+//
+// Warnings about deprecated or risky configuration are added to the given
+// context, see [addWarnings]. Their fields are prefixed with the given path,
+// which points to the provider config.
+func validateProviderConfig(
+	ctx context.Context,
+	decoder runtime.Decoder,
+	extensionType string,
+	path *field.Path,
+	providerConfig *runtime.RawExtension,
+) (config.ExampleConfig, error) {
+	var cfg config.ExampleConfig
+	if providerConfig == nil {
+		return cfg, &rejection{
+			reason: reasonMissingProviderConfig,
+			err:    fmt.Errorf("no provider config specified for %s", extensionType),
+		}
+	}
+
+	_, gvk, err := decoder.Decode(providerConfig.Raw, nil, &cfg)
+	if err != nil {
+		return cfg, &rejection{
+			reason: reasonInvalidProviderConfig,
+			err:    fmt.Errorf("invalid provider spec configuration for %s: %w", extensionType, err),
+		}
+	}
+
+	var apiVersion string
+	if gvk != nil {
+		apiVersion = gvk.GroupVersion().String()
+	}
+	addWarnings(ctx, validation.Deprecations.Warnings(path, apiVersion, cfg)...)
+
+	if err := validation.Validate(cfg); err != nil {
+		return cfg, &rejection{
+			reason: reasonInvalidConfiguration,
+			err:    fmt.Errorf("invalid extension configuration for %s: %w", extensionType, err),
+		}
+	}
+
+	// TODO(user): additional validation checks
+
+	return cfg, nil
+}
+
+// validateSecretRefs validates that the secrets referenced by the given
+// [config.ExampleConfig] of the extension with the given type are present in
+// the given resources of the shoot.
+func validateSecretRefs(extensionType string, cfg config.ExampleConfig, resources []core.NamedResourceReference) error {
+	if err := ValidateSecretRefs(cfg, resources).ToAggregate(); err != nil {
+		return &rejection{
+			reason: reasonInvalidSecretRefs,
+			err:    fmt.Errorf("invalid extension configuration for %s: %w", extensionType, err),
+		}
+	}
+
+	return nil
+}
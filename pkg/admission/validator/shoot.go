@@ -9,19 +9,27 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 
 	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
+	gardencorehelper "github.com/gardener/gardener/pkg/api/core/helper"
 	"github.com/gardener/gardener/pkg/apis/core"
 	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	gardenerutils "github.com/gardener/gardener/pkg/utils/gardener"
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	exampleactuator "gardener-extension-example/pkg/actuator/example"
 	"gardener-extension-example/pkg/apis/config"
-	"gardener-extension-example/pkg/apis/config/validation"
 )
 
 // ErrExtensionNotFound is an error, which is returned when the extension was
@@ -84,7 +92,22 @@ func (v *shootValidator) Validate(ctx context.Context, newObj, oldObj client.Obj
 		return nil
 	}
 
-	return v.validateExtension(newShoot, oldShoot)
+	operation := admissionv1.Create
+	if oldShoot != nil {
+		operation = admissionv1.Update
+	}
+
+	return admit(ctx, resourceShoot, operation, func() error {
+		// Updates of the shoot, which do not change the configuration
+		// of the extension, must be neither blocked nor warned about,
+		// e.g. due to a deprecation introduced after the configuration
+		// has been accepted. They are still admitted as allowed.
+		if oldShoot != nil && v.configUnchanged(newShoot, oldShoot) {
+			return nil
+		}
+
+		return v.validateExtension(ctx, newShoot, oldShoot)
+	})
 }
 
 // getExtension returns the [core.Extension] by extracting it from the given
@@ -105,9 +128,26 @@ func (v *shootValidator) getExtension(obj *core.Shoot) (core.Extension, error) {
 	return obj.Spec.Extensions[idx], nil
 }
 
+// configUnchanged returns true, if the extension is configured by both given
+// [core.Shoot] specs, and neither its configuration nor the resources of the
+// shoot, which may be referenced by it, differ semantically.
+func (v *shootValidator) configUnchanged(newObj, oldObj *core.Shoot) bool {
+	newExt, err := v.getExtension(newObj)
+	if err != nil {
+		return false
+	}
+	oldExt, err := v.getExtension(oldObj)
+	if err != nil {
+		return false
+	}
+
+	return equality.Semantic.DeepEqual(oldExt, newExt) &&
+		equality.Semantic.DeepEqual(oldObj.Spec.Resources, newObj.Spec.Resources)
+}
+
 // validateExtension validates the extension configuration from the given
 // [core.Shoot] specs.
-func (v *shootValidator) validateExtension(newObj *core.Shoot, _ *core.Shoot) error {
+func (v *shootValidator) validateExtension(ctx context.Context, newObj *core.Shoot, _ *core.Shoot) error {
 	ext, err := v.getExtension(newObj)
 	if err != nil {
 		return IgnoreExtensionNotFound(err)
@@ -118,22 +158,34 @@ func (v *shootValidator) validateExtension(newObj *core.Shoot, _ *core.Shoot) er
 		return nil
 	}
 
-	if ext.ProviderConfig == nil {
-		return fmt.Errorf("no provider config specified for %s", v.extensionType)
-	}
+	path := field.NewPath("spec", "extensions").Key(v.extensionType).Child("providerConfig")
 
-	var cfg config.ExampleConfig
-	if err := runtime.DecodeInto(v.decoder, ext.ProviderConfig.Raw, &cfg); err != nil {
-		return fmt.Errorf("invalid provider spec configuration for %s: %w", v.extensionType, err)
+	cfg, err := validateProviderConfig(ctx, v.decoder, v.extensionType, path, ext.ProviderConfig)
+	if err != nil {
+		return err
 	}
 
-	if err := validation.Validate(cfg); err != nil {
-		return fmt.Errorf("invalid extension configuration for %s: %w", v.extensionType, err)
-	}
+	return validateSecretRefs(v.extensionType, cfg, newObj.Spec.Resources)
+}
 
-	// TODO(user): additional validation checks
+// ValidateSecretRefs validates that the secrets referenced by the given
+// [config.ExampleConfig] are present in the given list of resources of a
+// [core.Shoot].
+func ValidateSecretRefs(cfg config.ExampleConfig, resources []core.NamedResourceReference) field.ErrorList {
+	allErrs := make(field.ErrorList, 0)
+
+	for i, ref := range cfg.Spec.SecretRefs {
+		path := field.NewPath("spec.secretRefs").Index(i).Child("name")
+		resource := gardencorehelper.GetResourceByName(resources, ref.Name)
+		switch {
+		case resource == nil:
+			allErrs = append(allErrs, field.NotFound(path, ref.Name))
+		case resource.ResourceRef.APIVersion != "v1" || resource.ResourceRef.Kind != "Secret":
+			allErrs = append(allErrs, field.Invalid(path, ref.Name, "referenced resource is not a secret"))
+		}
+	}
 
-	return nil
+	return allErrs
 }
 
 // NewShootValidatorWebhook returns a new validating [extensionswebhook.Webhook]
@@ -166,5 +218,68 @@ func NewShootValidatorWebhook(mgr manager.Manager) (*extensionswebhook.Webhook,
 		},
 	}
 
-	return extensionswebhook.New(mgr, args)
+	wh, err := extensionswebhook.New(mgr, args)
+	if err != nil {
+		return nil, err
+	}
+	wh.Webhook.LogConstructor = LogConstructor
+	wh.Webhook.Handler = NewWarningHandler(wh.Webhook.Handler)
+
+	return wh, nil
+}
+
+// LogConstructor returns a logger for the given admission request, which is
+// enriched with the UID of the request, the user, who sent it, and the shoot
+// and project it refers to, so that the logs of the webhook can be correlated
+// per shoot with the logs of the controller and the audit logs of the API
+// server. It is meant to be used as the LogConstructor of an
+// [admission.Webhook].
+//
+// For requests about [extensionsv1alpha1.Extension] resources, the shoot and
+// project are derived from the shoot namespace in the seed instead.
+func LogConstructor(base logr.Logger, req *admission.Request) logr.Logger {
+	if req == nil {
+		return base
+	}
+
+	values := []any{
+		"uid", req.UID,
+		"user", req.UserInfo.Username,
+		"operation", req.Operation,
+	}
+	if req.Kind.Group == extensionsv1alpha1.SchemeGroupVersion.Group {
+		project, shoot := shootOfNamespace(req.Namespace)
+		values = append(values, "shoot", shoot, "project", project, "extension", req.Name)
+	} else {
+		values = append(values, "shoot", req.Name, "project", projectName(req.Namespace))
+	}
+
+	return base.WithValues(values...)
+}
+
+// projectName returns the name of the project for the given project
+// namespace.
+func projectName(namespace string) string {
+	if namespace == v1beta1constants.GardenNamespace {
+		return namespace
+	}
+
+	return strings.TrimPrefix(namespace, gardenerutils.ProjectNamespacePrefix)
+}
+
+// shootOfNamespace returns the names of the project and the shoot for the
+// given shoot namespace in the seed, which is of the form
+// "shoot--<project>--<shoot>".
+func shootOfNamespace(namespace string) (string, string) {
+	rest, ok := strings.CutPrefix(namespace, v1beta1constants.TechnicalIDPrefix+"-")
+	if !ok {
+		return "", ""
+	}
+
+	project, shoot, ok := strings.Cut(rest, "--")
+	if !ok {
+		return "", ""
+	}
+
+	return project, shoot
 }
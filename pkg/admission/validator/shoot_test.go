@@ -8,16 +8,24 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"maps"
 
 	extensionswebhook "github.com/gardener/gardener/extensions/pkg/webhook"
 	"github.com/gardener/gardener/pkg/apis/core"
+	"github.com/go-logr/logr/funcr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	exampleactuator "gardener-extension-example/pkg/actuator/example"
 	"gardener-extension-example/pkg/admission/validator"
@@ -107,5 +115,279 @@ var _ = Describe("Shoot Validator", Ordered, func() {
 		Expect(err).To(MatchError(ContainSubstring("no provider config specified")))
 	})
 
+	It("should validate secret references against shoot resources", func() {
+		cfg := providerConfig.DeepCopy()
+		cfg.Spec.SecretRefs = []config.SecretReference{
+			{Name: "api-token"},
+			{Name: "missing"},
+			{Name: "not-a-secret"},
+		}
+		data, err := json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		shoot.Spec.Extensions = []core.Extension{
+			{
+				Type: exampleactuator.ExtensionType,
+				ProviderConfig: &runtime.RawExtension{
+					Raw: data,
+				},
+			},
+		}
+		shoot.Spec.Resources = []core.NamedResourceReference{
+			{
+				Name: "api-token",
+				ResourceRef: autoscalingv1.CrossVersionObjectReference{
+					APIVersion: "v1",
+					Kind:       "Secret",
+					Name:       "my-api-token",
+				},
+			},
+			{
+				Name: "not-a-secret",
+				ResourceRef: autoscalingv1.CrossVersionObjectReference{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Name:       "my-config",
+				},
+			},
+		}
+
+		err = shootValidator.Validate(ctx, shoot, nil)
+		Expect(err).To(MatchError(ContainSubstring(`spec.secretRefs[1].name: Not found: "missing"`)))
+		Expect(err).To(MatchError(ContainSubstring(`spec.secretRefs[2].name: Invalid value: "not-a-secret"`)))
+		Expect(err).NotTo(MatchError(ContainSubstring("secretRefs[0]")))
+
+		// Drop the invalid references
+		cfg.Spec.SecretRefs = cfg.Spec.SecretRefs[:1]
+		data, err = json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		shoot.Spec.Extensions[0].ProviderConfig.Raw = data
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+	})
+
+	It("should log rejections with the logger of the admission request", func() {
+		shoot.Spec.Extensions = []core.Extension{
+			{
+				Type: exampleactuator.ExtensionType,
+			},
+		}
+
+		var lines []string
+		logger := funcr.New(func(prefix, args string) {
+			lines = append(lines, args)
+		}, funcr.Options{})
+		req := &admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UID:       "8a9b4a5e-6f7c-4c1d-9b1e-2f3a4b5c6d7e",
+				Name:      shoot.Name,
+				Namespace: shoot.Namespace,
+				Operation: admissionv1.Update,
+				UserInfo:  authenticationv1.UserInfo{Username: "jane.doe@example.com"},
+			},
+		}
+		logCtx := logf.IntoContext(ctx, validator.LogConstructor(logger, req))
+
+		Expect(shootValidator.Validate(logCtx, shoot, nil)).To(MatchError(ContainSubstring("no provider config specified")))
+		Expect(lines).To(ConsistOf(SatisfyAll(
+			ContainSubstring(`"msg"="rejecting extension configuration"`),
+			ContainSubstring(`"uid"="8a9b4a5e-6f7c-4c1d-9b1e-2f3a4b5c6d7e"`),
+			ContainSubstring(`"user"="jane.doe@example.com"`),
+			ContainSubstring(`"operation"="UPDATE"`),
+			ContainSubstring(`"shoot"="local"`),
+			ContainSubstring(`"project"="local"`),
+			ContainSubstring(`"reason"="missing_provider_config"`),
+			ContainSubstring(`"fieldErrors"=["no provider config specified for example"]`),
+		)))
+	})
+
+	It("should audit each field error of a rejection", func() {
+		cfg := providerConfig.DeepCopy()
+		cfg.Spec.SecretRefs = []config.SecretReference{
+			{Name: "missing"},
+			{Name: "also-missing"},
+		}
+		data, err := json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		shoot.Spec.Extensions = []core.Extension{
+			{
+				Type: exampleactuator.ExtensionType,
+				ProviderConfig: &runtime.RawExtension{
+					Raw: data,
+				},
+			},
+		}
+
+		var lines []string
+		logger := funcr.New(func(prefix, args string) {
+			lines = append(lines, args)
+		}, funcr.Options{})
+		logCtx := logf.IntoContext(ctx, logger)
+
+		Expect(shootValidator.Validate(logCtx, shoot, nil)).NotTo(Succeed())
+		Expect(lines).To(ConsistOf(SatisfyAll(
+			ContainSubstring(`"reason"="invalid_secret_refs"`),
+			ContainSubstring(`"fieldErrors"=["spec.secretRefs[0].name: Not found: \"missing\"" "spec.secretRefs[1].name: Not found: \"also-missing\""]`),
+		)))
+	})
+
+	It("should record admission metrics by resource, operation, result and reason", func() {
+		deniedUpdates := admissionRequests("shoot", "UPDATE", "denied", "missing_provider_config")
+		allowedCreates := admissionRequests("shoot", "CREATE", "allowed", "none")
+		observations := admissionObservations("shoot", "UPDATE", "denied")
+
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+
+		oldShoot := shoot.DeepCopy()
+		shoot.Spec.Extensions = []core.Extension{
+			{
+				Type: exampleactuator.ExtensionType,
+			},
+		}
+		Expect(shootValidator.Validate(ctx, shoot, oldShoot)).NotTo(Succeed())
+
+		Expect(admissionRequests("shoot", "CREATE", "allowed", "none")).To(Equal(allowedCreates + 1))
+		Expect(admissionRequests("shoot", "UPDATE", "denied", "missing_provider_config")).To(Equal(deniedUpdates + 1))
+		Expect(admissionObservations("shoot", "UPDATE", "denied")).To(Equal(observations + 1))
+	})
+
+	It("should return warnings for risky configuration with the admission response", func() {
+		cfg := providerConfig.DeepCopy()
+		cfg.Spec.Foo = "bar "
+		data, err := json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		shoot.Spec.Extensions = []core.Extension{
+			{
+				Type: exampleactuator.ExtensionType,
+				ProviderConfig: &runtime.RawExtension{
+					Raw: data,
+				},
+			},
+		}
+
+		// Warnings are dropped without the warning handler
+		Expect(shootValidator.Validate(ctx, shoot, nil)).To(Succeed())
+
+		handler := validator.NewWarningHandler(admission.HandlerFunc(func(ctx context.Context, _ admission.Request) admission.Response {
+			if err := shootValidator.Validate(ctx, shoot, nil); err != nil {
+				return admission.Denied(err.Error())
+			}
+
+			return admission.Allowed("")
+		}))
+
+		resp := handler.Handle(ctx, admission.Request{})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(ConsistOf(
+			"spec.extensions[example].providerConfig.spec.foo: value has leading or trailing whitespace, which is not trimmed",
+		))
+
+		shoot.Spec.Extensions[0].ProviderConfig = &runtime.RawExtension{Raw: providerConfigData}
+		resp = handler.Handle(ctx, admission.Request{})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(BeEmpty())
+	})
+
+	It("should skip updates, which do not change the configuration of the extension", func() {
+		cfg := providerConfig.DeepCopy()
+		cfg.Spec.Foo = "bar "
+		data, err := json.Marshal(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		shoot.Spec.Extensions = []core.Extension{
+			{
+				Type: exampleactuator.ExtensionType,
+			},
+		}
+		denied := admissionRequests("shoot", "UPDATE", "denied", "missing_provider_config")
+		allowed := admissionRequests("shoot", "UPDATE", "allowed", "none")
+
+		// An invalid configuration, which has been accepted before, does
+		// not block unrelated updates, which are still counted as allowed
+		oldShoot := shoot.DeepCopy()
+		shoot.Labels = map[string]string{"foo": "bar"}
+		Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(Succeed())
+		Expect(admissionRequests("shoot", "UPDATE", "denied", "missing_provider_config")).To(Equal(denied))
+		Expect(admissionRequests("shoot", "UPDATE", "allowed", "none")).To(Equal(allowed + 1))
+
+		// Nor are warnings returned for it
+		shoot.Spec.Extensions[0].ProviderConfig = &runtime.RawExtension{Raw: data}
+		oldShoot = shoot.DeepCopy()
+		handler := validator.NewWarningHandler(admission.HandlerFunc(func(ctx context.Context, _ admission.Request) admission.Response {
+			if err := shootValidator.Validate(ctx, shoot, oldShoot); err != nil {
+				return admission.Denied(err.Error())
+			}
+
+			return admission.Allowed("")
+		}))
+		resp := handler.Handle(ctx, admission.Request{})
+		Expect(resp.Allowed).To(BeTrue())
+		Expect(resp.Warnings).To(BeEmpty())
+
+		// Changes of the referenced resources are still validated
+		shoot.Spec.Extensions[0].ProviderConfig = nil
+		oldShoot = shoot.DeepCopy()
+		shoot.Spec.Resources = []core.NamedResourceReference{
+			{
+				Name:        "foo",
+				ResourceRef: autoscalingv1.CrossVersionObjectReference{APIVersion: "v1", Kind: "Secret", Name: "foo"},
+			},
+		}
+		Expect(shootValidator.Validate(ctx, shoot, oldShoot)).To(MatchError(ContainSubstring("no provider config specified")))
+	})
+
+	It("should return the base logger without an admission request", func() {
+		logger := funcr.New(func(prefix, args string) {}, funcr.Options{})
+		Expect(validator.LogConstructor(logger, nil)).To(Equal(logger))
+	})
+
 	// TODO(user): additional tests
 })
+
+// admissionRequests returns the current value of the admission requests
+// counter with the given labels.
+func admissionRequests(resource, operation, result, reason string) float64 {
+	want := map[string]string{"resource": resource, "operation": operation, "result": result, "reason": reason}
+
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != "gardener_extension_example_admission_requests_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			got := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				got[label.GetName()] = label.GetValue()
+			}
+			if maps.Equal(got, want) {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
+
+// admissionObservations returns the current number of observations of the
+// admission duration histogram with the given labels.
+func admissionObservations(resource, operation, result string) uint64 {
+	want := map[string]string{"resource": resource, "operation": operation, "result": result}
+
+	families, err := ctrlmetrics.Registry.Gather()
+	Expect(err).NotTo(HaveOccurred())
+	for _, family := range families {
+		if family.GetName() != "gardener_extension_example_admission_duration_seconds" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			got := make(map[string]string)
+			for _, label := range m.GetLabel() {
+				got[label.GetName()] = label.GetValue()
+			}
+			if maps.Equal(got, want) {
+				return m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+
+	return 0
+}
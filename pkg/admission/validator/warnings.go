@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validator
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// warningsKey is the context key for the warnings of an admission request.
+type warningsKey struct{}
+
+// warnings collects the warnings of a single admission request.
+type warnings struct {
+	items []string
+}
+
+// addWarnings adds the given warnings to the admission request of the given
+// context. The warnings are dropped, unless the context has been prepared by
+// the [WarningHandler].
+func addWarnings(ctx context.Context, items ...string) {
+	w, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return
+	}
+
+	w.items = append(w.items, items...)
+}
+
+// WarningHandler is an [admission.Handler], which returns the warnings added
+// by the validators of the wrapped handler with the admission response, so that
+// they are shown to the user, e.g. by kubectl.
+//
+// The handlers of Gardener extension webhooks can only allow or deny an
+// admission request, so the warnings are passed via the context instead.
+type WarningHandler struct {
+	handler admission.Handler
+}
+
+var _ admission.Handler = &WarningHandler{}
+
+// NewWarningHandler returns a new [WarningHandler], which wraps the given
+// [admission.Handler].
+func NewWarningHandler(handler admission.Handler) *WarningHandler {
+	h := &WarningHandler{
+		handler: handler,
+	}
+
+	return h
+}
+
+// Handle implements the [admission.Handler] interface.
+func (h *WarningHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	w := &warnings{}
+	resp := h.handler.Handle(context.WithValue(ctx, warningsKey{}, w), req)
+	resp.Warnings = append(resp.Warnings, w.items...)
+
+	return resp
+}
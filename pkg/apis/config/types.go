@@ -5,6 +5,7 @@
 package config
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,9 +14,22 @@ type ExampleConfigSpec struct {
 	// Foo is foo
 	Foo string
 
+	// SecretRefs are references to secrets from the resources of the
+	// shoot, which are projected into the seed-side workload.
+	SecretRefs []SecretReference
+
 	// TODO(user): insert additional spec fields
 }
 
+// SecretReference references a secret from the resources of the shoot. The
+// referenced secret is copied by gardenlet into the shoot namespace in the
+// seed cluster.
+type SecretReference struct {
+	// Name is the name of the resource reference in the spec.resources of
+	// the shoot.
+	Name string
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ExampleConfig is the schema for the API
@@ -25,3 +39,164 @@ type ExampleConfig struct {
 	// Spec provides the extension configuration spec.
 	Spec ExampleConfigSpec
 }
+
+// HibernationPhase describes the hibernation phase of the seed-side components
+// managed by the extension.
+type HibernationPhase string
+
+const (
+	// HibernationPhaseAwake means that the seed-side components are
+	// deployed and running.
+	HibernationPhaseAwake HibernationPhase = "Awake"
+	// HibernationPhaseHibernating means that the seed-side components are
+	// being scaled down.
+	HibernationPhaseHibernating HibernationPhase = "Hibernating"
+	// HibernationPhaseHibernated means that the seed-side components have
+	// been scaled down.
+	HibernationPhaseHibernated HibernationPhase = "Hibernated"
+	// HibernationPhaseWakingUp means that the seed-side components are
+	// being restored after hibernation.
+	HibernationPhaseWakingUp HibernationPhase = "WakingUp"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExampleStatus is the provider status of the extension
+type ExampleStatus struct {
+	metav1.TypeMeta
+
+	// HibernationPhase is the hibernation phase of the seed-side
+	// components managed by the extension.
+	HibernationPhase HibernationPhase
+
+	// Plan is the plan of changes computed by the extension in dry-run
+	// mode.
+	Plan *Plan
+
+	// Checksum is the checksum of the inputs of the last successful full
+	// reconciliation, e.g. the provider config, the relevant fields of the
+	// cluster and the version of the extension.
+	Checksum string
+
+	// LastFullReconcileTime is the time of the last successful full
+	// reconciliation.
+	LastFullReconcileTime *metav1.Time
+
+	// LastCustomOperation is the result of the last custom operation, which
+	// has been requested via annotation on the Extension or Shoot resource.
+	LastCustomOperation *CustomOperationResult
+
+	// ObservedShootOperation is the custom operation requested via
+	// annotation on the Shoot resource, which has been performed already.
+	// Since the extension cannot remove the annotation from the Shoot, the
+	// operation is not repeated as long as the annotation is unchanged.
+	ObservedShootOperation string
+}
+
+// CustomOperationState is the state of a completed custom operation.
+type CustomOperationState string
+
+const (
+	// CustomOperationStateSucceeded means that the custom operation has
+	// been completed successfully.
+	CustomOperationStateSucceeded CustomOperationState = "Succeeded"
+	// CustomOperationStateFailed means that the custom operation has
+	// failed.
+	CustomOperationStateFailed CustomOperationState = "Failed"
+)
+
+// CustomOperationResult is the result of a custom operation, which has been
+// requested via annotation on the Extension or Shoot resource.
+type CustomOperationResult struct {
+	// Operation is the name of the operation, e.g. rotate-credentials.
+	Operation string
+
+	// Source is the kind of the resource, which requested the operation,
+	// i.e. Extension or Shoot.
+	Source string
+
+	// State is the state of the completed operation.
+	State CustomOperationState
+
+	// Description describes the outcome of the operation.
+	Description string
+
+	// Details are additional details reported by the operation, e.g. the
+	// collected diagnostics.
+	Details []string
+
+	// CompletionTime is the time, when the operation has been completed.
+	CompletionTime metav1.Time
+}
+
+// PlannedAction describes an action, which the extension would take on an
+// object, if it was not running in dry-run mode.
+type PlannedAction string
+
+const (
+	// PlannedActionCreate means that the object would be created.
+	PlannedActionCreate PlannedAction = "Create"
+	// PlannedActionUpdate means that the object would be updated.
+	PlannedActionUpdate PlannedAction = "Update"
+	// PlannedActionDelete means that the object would be deleted.
+	PlannedActionDelete PlannedAction = "Delete"
+)
+
+// Plan is the plan of changes computed by the extension in dry-run mode.
+type Plan struct {
+	// Operation is the operation for which the plan was computed, e.g.
+	// reconcile or delete.
+	Operation string
+
+	// Changes are the changes, which the extension would apply.
+	Changes []PlannedChange
+}
+
+// PlannedChange is a change of a single object, which the extension would
+// apply, if it was not running in dry-run mode.
+type PlannedChange struct {
+	// Action is the action, which would be taken on the object.
+	Action PlannedAction
+
+	// Kind is the kind of the object.
+	Kind string
+
+	// Namespace is the namespace of the object.
+	Namespace string
+
+	// Name is the name of the object.
+	Name string
+
+	// Component is the name of the seed-side component, which the object
+	// belongs to, if any.
+	Component string
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExampleState is the state of the extension, which is persisted in the
+// Extension resource, so that it can be restored after a control plane
+// migration.
+type ExampleState struct {
+	metav1.TypeMeta
+
+	// Secrets are the secrets generated by the secrets manager of the
+	// extension, which must be persisted.
+	Secrets []PersistedSecret
+}
+
+// PersistedSecret is a secret generated by the secrets manager of the
+// extension, which is persisted in the [ExampleState].
+type PersistedSecret struct {
+	// Name is the name of the secret.
+	Name string
+
+	// Labels are the labels of the secret.
+	Labels map[string]string
+
+	// Type is the type of the secret.
+	Type corev1.SecretType
+
+	// Data is the data of the secret.
+	Data map[string][]byte
+}
@@ -7,7 +7,10 @@ package v1alpha1
 
 import (
 	config "gardener-extension-example/pkg/apis/config"
+	unsafe "unsafe"
 
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -19,6 +22,16 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*CustomOperationResult)(nil), (*config.CustomOperationResult)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CustomOperationResult_To_config_CustomOperationResult(a.(*CustomOperationResult), b.(*config.CustomOperationResult), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CustomOperationResult)(nil), (*CustomOperationResult)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CustomOperationResult_To_v1alpha1_CustomOperationResult(a.(*config.CustomOperationResult), b.(*CustomOperationResult), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ExampleConfig)(nil), (*config.ExampleConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ExampleConfig_To_config_ExampleConfig(a.(*ExampleConfig), b.(*config.ExampleConfig), scope)
 	}); err != nil {
@@ -39,9 +52,99 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ExampleState)(nil), (*config.ExampleState)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ExampleState_To_config_ExampleState(a.(*ExampleState), b.(*config.ExampleState), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ExampleState)(nil), (*ExampleState)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ExampleState_To_v1alpha1_ExampleState(a.(*config.ExampleState), b.(*ExampleState), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ExampleStatus)(nil), (*config.ExampleStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ExampleStatus_To_config_ExampleStatus(a.(*ExampleStatus), b.(*config.ExampleStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ExampleStatus)(nil), (*ExampleStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ExampleStatus_To_v1alpha1_ExampleStatus(a.(*config.ExampleStatus), b.(*ExampleStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PersistedSecret)(nil), (*config.PersistedSecret)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PersistedSecret_To_config_PersistedSecret(a.(*PersistedSecret), b.(*config.PersistedSecret), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PersistedSecret)(nil), (*PersistedSecret)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PersistedSecret_To_v1alpha1_PersistedSecret(a.(*config.PersistedSecret), b.(*PersistedSecret), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*Plan)(nil), (*config.Plan)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Plan_To_config_Plan(a.(*Plan), b.(*config.Plan), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.Plan)(nil), (*Plan)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_Plan_To_v1alpha1_Plan(a.(*config.Plan), b.(*Plan), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*PlannedChange)(nil), (*config.PlannedChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PlannedChange_To_config_PlannedChange(a.(*PlannedChange), b.(*config.PlannedChange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.PlannedChange)(nil), (*PlannedChange)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_PlannedChange_To_v1alpha1_PlannedChange(a.(*config.PlannedChange), b.(*PlannedChange), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SecretReference)(nil), (*config.SecretReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SecretReference_To_config_SecretReference(a.(*SecretReference), b.(*config.SecretReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SecretReference)(nil), (*SecretReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SecretReference_To_v1alpha1_SecretReference(a.(*config.SecretReference), b.(*SecretReference), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
+func autoConvert_v1alpha1_CustomOperationResult_To_config_CustomOperationResult(in *CustomOperationResult, out *config.CustomOperationResult, s conversion.Scope) error {
+	out.Operation = in.Operation
+	out.Source = in.Source
+	out.State = config.CustomOperationState(in.State)
+	out.Description = in.Description
+	out.Details = *(*[]string)(unsafe.Pointer(&in.Details))
+	out.CompletionTime = in.CompletionTime
+	return nil
+}
+
+// Convert_v1alpha1_CustomOperationResult_To_config_CustomOperationResult is an autogenerated conversion function.
+func Convert_v1alpha1_CustomOperationResult_To_config_CustomOperationResult(in *CustomOperationResult, out *config.CustomOperationResult, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CustomOperationResult_To_config_CustomOperationResult(in, out, s)
+}
+
+func autoConvert_config_CustomOperationResult_To_v1alpha1_CustomOperationResult(in *config.CustomOperationResult, out *CustomOperationResult, s conversion.Scope) error {
+	out.Operation = in.Operation
+	out.Source = in.Source
+	out.State = CustomOperationState(in.State)
+	out.Description = in.Description
+	out.Details = *(*[]string)(unsafe.Pointer(&in.Details))
+	out.CompletionTime = in.CompletionTime
+	return nil
+}
+
+// Convert_config_CustomOperationResult_To_v1alpha1_CustomOperationResult is an autogenerated conversion function.
+func Convert_config_CustomOperationResult_To_v1alpha1_CustomOperationResult(in *config.CustomOperationResult, out *CustomOperationResult, s conversion.Scope) error {
+	return autoConvert_config_CustomOperationResult_To_v1alpha1_CustomOperationResult(in, out, s)
+}
+
 func autoConvert_v1alpha1_ExampleConfig_To_config_ExampleConfig(in *ExampleConfig, out *config.ExampleConfig, s conversion.Scope) error {
 	if err := Convert_v1alpha1_ExampleConfigSpec_To_config_ExampleConfigSpec(&in.Spec, &out.Spec, s); err != nil {
 		return err
@@ -68,6 +171,7 @@ func Convert_config_ExampleConfig_To_v1alpha1_ExampleConfig(in *config.ExampleCo
 
 func autoConvert_v1alpha1_ExampleConfigSpec_To_config_ExampleConfigSpec(in *ExampleConfigSpec, out *config.ExampleConfigSpec, s conversion.Scope) error {
 	out.Foo = in.Foo
+	out.SecretRefs = *(*[]config.SecretReference)(unsafe.Pointer(&in.SecretRefs))
 	return nil
 }
 
@@ -78,6 +182,7 @@ func Convert_v1alpha1_ExampleConfigSpec_To_config_ExampleConfigSpec(in *ExampleC
 
 func autoConvert_config_ExampleConfigSpec_To_v1alpha1_ExampleConfigSpec(in *config.ExampleConfigSpec, out *ExampleConfigSpec, s conversion.Scope) error {
 	out.Foo = in.Foo
+	out.SecretRefs = *(*[]SecretReference)(unsafe.Pointer(&in.SecretRefs))
 	return nil
 }
 
@@ -85,3 +190,149 @@ func autoConvert_config_ExampleConfigSpec_To_v1alpha1_ExampleConfigSpec(in *conf
 func Convert_config_ExampleConfigSpec_To_v1alpha1_ExampleConfigSpec(in *config.ExampleConfigSpec, out *ExampleConfigSpec, s conversion.Scope) error {
 	return autoConvert_config_ExampleConfigSpec_To_v1alpha1_ExampleConfigSpec(in, out, s)
 }
+
+func autoConvert_v1alpha1_ExampleState_To_config_ExampleState(in *ExampleState, out *config.ExampleState, s conversion.Scope) error {
+	out.Secrets = *(*[]config.PersistedSecret)(unsafe.Pointer(&in.Secrets))
+	return nil
+}
+
+// Convert_v1alpha1_ExampleState_To_config_ExampleState is an autogenerated conversion function.
+func Convert_v1alpha1_ExampleState_To_config_ExampleState(in *ExampleState, out *config.ExampleState, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ExampleState_To_config_ExampleState(in, out, s)
+}
+
+func autoConvert_config_ExampleState_To_v1alpha1_ExampleState(in *config.ExampleState, out *ExampleState, s conversion.Scope) error {
+	out.Secrets = *(*[]PersistedSecret)(unsafe.Pointer(&in.Secrets))
+	return nil
+}
+
+// Convert_config_ExampleState_To_v1alpha1_ExampleState is an autogenerated conversion function.
+func Convert_config_ExampleState_To_v1alpha1_ExampleState(in *config.ExampleState, out *ExampleState, s conversion.Scope) error {
+	return autoConvert_config_ExampleState_To_v1alpha1_ExampleState(in, out, s)
+}
+
+func autoConvert_v1alpha1_ExampleStatus_To_config_ExampleStatus(in *ExampleStatus, out *config.ExampleStatus, s conversion.Scope) error {
+	out.HibernationPhase = config.HibernationPhase(in.HibernationPhase)
+	out.Plan = (*config.Plan)(unsafe.Pointer(in.Plan))
+	out.Checksum = in.Checksum
+	out.LastFullReconcileTime = (*v1.Time)(unsafe.Pointer(in.LastFullReconcileTime))
+	out.LastCustomOperation = (*config.CustomOperationResult)(unsafe.Pointer(in.LastCustomOperation))
+	out.ObservedShootOperation = in.ObservedShootOperation
+	return nil
+}
+
+// Convert_v1alpha1_ExampleStatus_To_config_ExampleStatus is an autogenerated conversion function.
+func Convert_v1alpha1_ExampleStatus_To_config_ExampleStatus(in *ExampleStatus, out *config.ExampleStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ExampleStatus_To_config_ExampleStatus(in, out, s)
+}
+
+func autoConvert_config_ExampleStatus_To_v1alpha1_ExampleStatus(in *config.ExampleStatus, out *ExampleStatus, s conversion.Scope) error {
+	out.HibernationPhase = HibernationPhase(in.HibernationPhase)
+	out.Plan = (*Plan)(unsafe.Pointer(in.Plan))
+	out.Checksum = in.Checksum
+	out.LastFullReconcileTime = (*v1.Time)(unsafe.Pointer(in.LastFullReconcileTime))
+	out.LastCustomOperation = (*CustomOperationResult)(unsafe.Pointer(in.LastCustomOperation))
+	out.ObservedShootOperation = in.ObservedShootOperation
+	return nil
+}
+
+// Convert_config_ExampleStatus_To_v1alpha1_ExampleStatus is an autogenerated conversion function.
+func Convert_config_ExampleStatus_To_v1alpha1_ExampleStatus(in *config.ExampleStatus, out *ExampleStatus, s conversion.Scope) error {
+	return autoConvert_config_ExampleStatus_To_v1alpha1_ExampleStatus(in, out, s)
+}
+
+func autoConvert_v1alpha1_PersistedSecret_To_config_PersistedSecret(in *PersistedSecret, out *config.PersistedSecret, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Type = corev1.SecretType(in.Type)
+	out.Data = *(*map[string][]byte)(unsafe.Pointer(&in.Data))
+	return nil
+}
+
+// Convert_v1alpha1_PersistedSecret_To_config_PersistedSecret is an autogenerated conversion function.
+func Convert_v1alpha1_PersistedSecret_To_config_PersistedSecret(in *PersistedSecret, out *config.PersistedSecret, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PersistedSecret_To_config_PersistedSecret(in, out, s)
+}
+
+func autoConvert_config_PersistedSecret_To_v1alpha1_PersistedSecret(in *config.PersistedSecret, out *PersistedSecret, s conversion.Scope) error {
+	out.Name = in.Name
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Type = corev1.SecretType(in.Type)
+	out.Data = *(*map[string][]byte)(unsafe.Pointer(&in.Data))
+	return nil
+}
+
+// Convert_config_PersistedSecret_To_v1alpha1_PersistedSecret is an autogenerated conversion function.
+func Convert_config_PersistedSecret_To_v1alpha1_PersistedSecret(in *config.PersistedSecret, out *PersistedSecret, s conversion.Scope) error {
+	return autoConvert_config_PersistedSecret_To_v1alpha1_PersistedSecret(in, out, s)
+}
+
+func autoConvert_v1alpha1_Plan_To_config_Plan(in *Plan, out *config.Plan, s conversion.Scope) error {
+	out.Operation = in.Operation
+	out.Changes = *(*[]config.PlannedChange)(unsafe.Pointer(&in.Changes))
+	return nil
+}
+
+// Convert_v1alpha1_Plan_To_config_Plan is an autogenerated conversion function.
+func Convert_v1alpha1_Plan_To_config_Plan(in *Plan, out *config.Plan, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Plan_To_config_Plan(in, out, s)
+}
+
+func autoConvert_config_Plan_To_v1alpha1_Plan(in *config.Plan, out *Plan, s conversion.Scope) error {
+	out.Operation = in.Operation
+	out.Changes = *(*[]PlannedChange)(unsafe.Pointer(&in.Changes))
+	return nil
+}
+
+// Convert_config_Plan_To_v1alpha1_Plan is an autogenerated conversion function.
+func Convert_config_Plan_To_v1alpha1_Plan(in *config.Plan, out *Plan, s conversion.Scope) error {
+	return autoConvert_config_Plan_To_v1alpha1_Plan(in, out, s)
+}
+
+func autoConvert_v1alpha1_PlannedChange_To_config_PlannedChange(in *PlannedChange, out *config.PlannedChange, s conversion.Scope) error {
+	out.Action = config.PlannedAction(in.Action)
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.Component = in.Component
+	return nil
+}
+
+// Convert_v1alpha1_PlannedChange_To_config_PlannedChange is an autogenerated conversion function.
+func Convert_v1alpha1_PlannedChange_To_config_PlannedChange(in *PlannedChange, out *config.PlannedChange, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PlannedChange_To_config_PlannedChange(in, out, s)
+}
+
+func autoConvert_config_PlannedChange_To_v1alpha1_PlannedChange(in *config.PlannedChange, out *PlannedChange, s conversion.Scope) error {
+	out.Action = PlannedAction(in.Action)
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.Component = in.Component
+	return nil
+}
+
+// Convert_config_PlannedChange_To_v1alpha1_PlannedChange is an autogenerated conversion function.
+func Convert_config_PlannedChange_To_v1alpha1_PlannedChange(in *config.PlannedChange, out *PlannedChange, s conversion.Scope) error {
+	return autoConvert_config_PlannedChange_To_v1alpha1_PlannedChange(in, out, s)
+}
+
+func autoConvert_v1alpha1_SecretReference_To_config_SecretReference(in *SecretReference, out *config.SecretReference, s conversion.Scope) error {
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1alpha1_SecretReference_To_config_SecretReference is an autogenerated conversion function.
+func Convert_v1alpha1_SecretReference_To_config_SecretReference(in *SecretReference, out *config.SecretReference, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SecretReference_To_config_SecretReference(in, out, s)
+}
+
+func autoConvert_config_SecretReference_To_v1alpha1_SecretReference(in *config.SecretReference, out *SecretReference, s conversion.Scope) error {
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_config_SecretReference_To_v1alpha1_SecretReference is an autogenerated conversion function.
+func Convert_config_SecretReference_To_v1alpha1_SecretReference(in *config.SecretReference, out *SecretReference, s conversion.Scope) error {
+	return autoConvert_config_SecretReference_To_v1alpha1_SecretReference(in, out, s)
+}
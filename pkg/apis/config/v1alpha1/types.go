@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -13,9 +14,22 @@ type ExampleConfigSpec struct {
 	// Foo is foo
 	Foo string `json:"foo,omitzero"`
 
+	// SecretRefs are references to secrets from the resources of the
+	// shoot, which are projected into the seed-side workload.
+	SecretRefs []SecretReference `json:"secretRefs,omitempty"`
+
 	// TODO(user): insert additional spec fields
 }
 
+// SecretReference references a secret from the resources of the shoot. The
+// referenced secret is copied by gardenlet into the shoot namespace in the
+// seed cluster.
+type SecretReference struct {
+	// Name is the name of the resource reference in the spec.resources of
+	// the shoot.
+	Name string `json:"name"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ExampleConfig is the schema for the API
@@ -25,3 +39,164 @@ type ExampleConfig struct {
 	// Spec provides the extension configuration spec.
 	Spec ExampleConfigSpec `json:"spec,omitzero"`
 }
+
+// HibernationPhase describes the hibernation phase of the seed-side components
+// managed by the extension.
+type HibernationPhase string
+
+const (
+	// HibernationPhaseAwake means that the seed-side components are
+	// deployed and running.
+	HibernationPhaseAwake HibernationPhase = "Awake"
+	// HibernationPhaseHibernating means that the seed-side components are
+	// being scaled down.
+	HibernationPhaseHibernating HibernationPhase = "Hibernating"
+	// HibernationPhaseHibernated means that the seed-side components have
+	// been scaled down.
+	HibernationPhaseHibernated HibernationPhase = "Hibernated"
+	// HibernationPhaseWakingUp means that the seed-side components are
+	// being restored after hibernation.
+	HibernationPhaseWakingUp HibernationPhase = "WakingUp"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExampleStatus is the provider status of the extension
+type ExampleStatus struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// HibernationPhase is the hibernation phase of the seed-side
+	// components managed by the extension.
+	HibernationPhase HibernationPhase `json:"hibernationPhase,omitzero"`
+
+	// Plan is the plan of changes computed by the extension in dry-run
+	// mode.
+	Plan *Plan `json:"plan,omitempty"`
+
+	// Checksum is the checksum of the inputs of the last successful full
+	// reconciliation, e.g. the provider config, the relevant fields of the
+	// cluster and the version of the extension.
+	Checksum string `json:"checksum,omitempty"`
+
+	// LastFullReconcileTime is the time of the last successful full
+	// reconciliation.
+	LastFullReconcileTime *metav1.Time `json:"lastFullReconcileTime,omitempty"`
+
+	// LastCustomOperation is the result of the last custom operation, which
+	// has been requested via annotation on the Extension or Shoot resource.
+	LastCustomOperation *CustomOperationResult `json:"lastCustomOperation,omitempty"`
+
+	// ObservedShootOperation is the custom operation requested via
+	// annotation on the Shoot resource, which has been performed already.
+	// Since the extension cannot remove the annotation from the Shoot, the
+	// operation is not repeated as long as the annotation is unchanged.
+	ObservedShootOperation string `json:"observedShootOperation,omitzero"`
+}
+
+// CustomOperationState is the state of a completed custom operation.
+type CustomOperationState string
+
+const (
+	// CustomOperationStateSucceeded means that the custom operation has
+	// been completed successfully.
+	CustomOperationStateSucceeded CustomOperationState = "Succeeded"
+	// CustomOperationStateFailed means that the custom operation has
+	// failed.
+	CustomOperationStateFailed CustomOperationState = "Failed"
+)
+
+// CustomOperationResult is the result of a custom operation, which has been
+// requested via annotation on the Extension or Shoot resource.
+type CustomOperationResult struct {
+	// Operation is the name of the operation, e.g. rotate-credentials.
+	Operation string `json:"operation"`
+
+	// Source is the kind of the resource, which requested the operation,
+	// i.e. Extension or Shoot.
+	Source string `json:"source"`
+
+	// State is the state of the completed operation.
+	State CustomOperationState `json:"state"`
+
+	// Description describes the outcome of the operation.
+	Description string `json:"description,omitzero"`
+
+	// Details are additional details reported by the operation, e.g. the
+	// collected diagnostics.
+	Details []string `json:"details,omitempty"`
+
+	// CompletionTime is the time, when the operation has been completed.
+	CompletionTime metav1.Time `json:"completionTime"`
+}
+
+// PlannedAction describes an action, which the extension would take on an
+// object, if it was not running in dry-run mode.
+type PlannedAction string
+
+const (
+	// PlannedActionCreate means that the object would be created.
+	PlannedActionCreate PlannedAction = "Create"
+	// PlannedActionUpdate means that the object would be updated.
+	PlannedActionUpdate PlannedAction = "Update"
+	// PlannedActionDelete means that the object would be deleted.
+	PlannedActionDelete PlannedAction = "Delete"
+)
+
+// Plan is the plan of changes computed by the extension in dry-run mode.
+type Plan struct {
+	// Operation is the operation for which the plan was computed, e.g.
+	// reconcile or delete.
+	Operation string `json:"operation"`
+
+	// Changes are the changes, which the extension would apply.
+	Changes []PlannedChange `json:"changes,omitempty"`
+}
+
+// PlannedChange is a change of a single object, which the extension would
+// apply, if it was not running in dry-run mode.
+type PlannedChange struct {
+	// Action is the action, which would be taken on the object.
+	Action PlannedAction `json:"action"`
+
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object.
+	Namespace string `json:"namespace,omitzero"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// Component is the name of the seed-side component, which the object
+	// belongs to, if any.
+	Component string `json:"component,omitzero"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExampleState is the state of the extension, which is persisted in the
+// Extension resource, so that it can be restored after a control plane
+// migration.
+type ExampleState struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Secrets are the secrets generated by the secrets manager of the
+	// extension, which must be persisted.
+	Secrets []PersistedSecret `json:"secrets,omitempty"`
+}
+
+// PersistedSecret is a secret generated by the secrets manager of the
+// extension, which is persisted in the [ExampleState].
+type PersistedSecret struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+
+	// Labels are the labels of the secret.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Type is the type of the secret.
+	Type corev1.SecretType `json:"type,omitzero"`
+
+	// Data is the data of the secret.
+	Data map[string][]byte `json:"data,omitempty"`
+}
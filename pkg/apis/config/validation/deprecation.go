@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"gardener-extension-example/pkg/apis/config"
+)
+
+// DeprecationKind categorizes a [Deprecation].
+type DeprecationKind string
+
+const (
+	// DeprecationKindAPIVersion means that the API version of the
+	// configuration is deprecated.
+	DeprecationKindAPIVersion DeprecationKind = "DeprecatedAPIVersion"
+	// DeprecationKindField means that a field of the configuration is
+	// deprecated.
+	DeprecationKindField DeprecationKind = "DeprecatedField"
+	// DeprecationKindDefaultChange means that the default of a field of the
+	// configuration will change in the next release.
+	DeprecationKindDefaultChange DeprecationKind = "DefaultChange"
+	// DeprecationKindRisky means that the configuration is valid, but
+	// likely not what the user intended.
+	DeprecationKindRisky DeprecationKind = "RiskyConfiguration"
+)
+
+// Deprecation describes a valid configuration, for which a warning is returned
+// to the user.
+type Deprecation struct {
+	// Kind categorizes the deprecation.
+	Kind DeprecationKind
+
+	// APIVersion restricts the deprecation to configurations of the given
+	// API version, e.g. the deprecated API version for
+	// [DeprecationKindAPIVersion]. An empty API version matches all
+	// configurations.
+	APIVersion string
+
+	// Field is the path of the field within the configuration, e.g.
+	// "spec.foo".
+	Field string
+
+	// Message tells the user what is wrong and what to do instead.
+	Message string
+
+	// Applies reports whether the deprecation applies to the given
+	// configuration. A nil func matches all configurations.
+	Applies func(cfg config.ExampleConfig) bool
+}
+
+// DeprecationTable is a list of [Deprecation] entries, which are checked in
+// order.
+type DeprecationTable []Deprecation
+
+// Deprecations is the [DeprecationTable] of the [config.ExampleConfig], which
+// is checked by the admission webhook.
+var Deprecations = DeprecationTable{
+	{
+		Kind:    DeprecationKindRisky,
+		Field:   "spec.foo",
+		Message: "value has leading or trailing whitespace, which is not trimmed",
+		Applies: func(cfg config.ExampleConfig) bool {
+			return cfg.Spec.Foo != strings.TrimSpace(cfg.Spec.Foo)
+		},
+	},
+
+	// TODO(user): add the deprecated API versions and fields of the
+	// configuration and the fields, whose defaults change in the next
+	// release, e.g.
+	//
+	//	{
+	//		Kind:       DeprecationKindAPIVersion,
+	//		APIVersion: "example.extensions.gardener.cloud/v1alpha1",
+	//		Field:      "apiVersion",
+	//		Message:    "use example.extensions.gardener.cloud/v1beta1 instead",
+	//	},
+}
+
+// Warnings returns a warning for each [Deprecation] of the table, which
+// applies to the given [config.ExampleConfig] of the given API version. The
+// fields of the warnings are prefixed with the given path, which points to
+// the configuration, e.g. within a Shoot.
+func (t DeprecationTable) Warnings(path *field.Path, apiVersion string, cfg config.ExampleConfig) []string {
+	warnings := make([]string, 0)
+
+	for _, d := range t {
+		if d.APIVersion != "" && d.APIVersion != apiVersion {
+			continue
+		}
+		if d.Applies != nil && !d.Applies(cfg) {
+			continue
+		}
+
+		fieldPath := d.Field
+		if path != nil {
+			fieldPath = path.String() + "." + d.Field
+		}
+		warnings = append(warnings, fmt.Sprintf("%s: %s", fieldPath, d.Message))
+	}
+
+	return warnings
+}
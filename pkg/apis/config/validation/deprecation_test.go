@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"gardener-extension-example/pkg/apis/config"
+	"gardener-extension-example/pkg/apis/config/validation"
+)
+
+var _ = Describe("Deprecation Tests", func() {
+	const (
+		deprecatedVersion = "example.extensions.gardener.cloud/v1alpha1"
+		currentVersion    = "example.extensions.gardener.cloud/v1beta1"
+	)
+
+	var (
+		cfg   config.ExampleConfig
+		table = validation.DeprecationTable{
+			{
+				Kind:       validation.DeprecationKindAPIVersion,
+				APIVersion: deprecatedVersion,
+				Field:      "apiVersion",
+				Message:    "use " + currentVersion + " instead",
+			},
+			{
+				Kind:    validation.DeprecationKindField,
+				Field:   "spec.foo",
+				Message: "use spec.bar instead",
+				Applies: func(cfg config.ExampleConfig) bool {
+					return cfg.Spec.Foo != ""
+				},
+			},
+			{
+				Kind:    validation.DeprecationKindDefaultChange,
+				Field:   "spec.secretRefs",
+				Message: "secrets will no longer be projected by default",
+				Applies: func(cfg config.ExampleConfig) bool {
+					return len(cfg.Spec.SecretRefs) == 0
+				},
+			},
+		}
+	)
+
+	BeforeEach(func() {
+		cfg = config.ExampleConfig{
+			Spec: config.ExampleConfigSpec{
+				Foo: "bar",
+			},
+		}
+	})
+
+	It("should return a warning for each applicable entry", func() {
+		Expect(table.Warnings(nil, deprecatedVersion, cfg)).To(Equal([]string{
+			"apiVersion: use example.extensions.gardener.cloud/v1beta1 instead",
+			"spec.foo: use spec.bar instead",
+			"spec.secretRefs: secrets will no longer be projected by default",
+		}))
+	})
+
+	It("should only warn about the API version of the configuration", func() {
+		cfg.Spec.Foo = ""
+		cfg.Spec.SecretRefs = []config.SecretReference{{Name: "api-token"}}
+		Expect(table.Warnings(nil, currentVersion, cfg)).To(BeEmpty())
+	})
+
+	It("should prefix the fields with the given path", func() {
+		path := field.NewPath("spec", "extensions").Index(0).Child("providerConfig")
+		Expect(table.Warnings(path, currentVersion, cfg)).To(ConsistOf(
+			"spec.extensions[0].providerConfig.spec.foo: use spec.bar instead",
+			"spec.extensions[0].providerConfig.spec.secretRefs: secrets will no longer be projected by default",
+		))
+	})
+
+	It("should warn about leading or trailing whitespace", func() {
+		Expect(validation.Deprecations.Warnings(nil, deprecatedVersion, cfg)).To(BeEmpty())
+
+		cfg.Spec.Foo = " bar"
+		Expect(validation.Deprecations.Warnings(nil, deprecatedVersion, cfg)).To(ConsistOf(
+			ContainSubstring("spec.foo: value has leading or trailing whitespace"),
+		))
+	})
+
+	It("should categorize each entry of the deprecation table", func() {
+		for _, d := range validation.Deprecations {
+			Expect(d.Kind).To(BeElementOf(
+				validation.DeprecationKindAPIVersion,
+				validation.DeprecationKindField,
+				validation.DeprecationKindDefaultChange,
+				validation.DeprecationKindRisky,
+			))
+			Expect(d.Field).NotTo(BeEmpty())
+			Expect(d.Message).NotTo(BeEmpty())
+		}
+	})
+})
@@ -21,6 +21,18 @@ func Validate(cfg config.ExampleConfig) error {
 		)
 	}
 
+	seen := make(map[string]bool, len(cfg.Spec.SecretRefs))
+	for i, ref := range cfg.Spec.SecretRefs {
+		path := field.NewPath("spec.secretRefs").Index(i).Child("name")
+		switch {
+		case ref.Name == "":
+			allErrs = append(allErrs, field.Required(path, "empty value specified"))
+		case seen[ref.Name]:
+			allErrs = append(allErrs, field.Duplicate(path, ref.Name))
+		}
+		seen[ref.Name] = true
+	}
+
 	// TODO(user): validate any other config setting
 
 	return allErrs.ToAggregate()
@@ -29,5 +29,35 @@ var _ = Describe("Validation Tests", Ordered, func() {
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should detect invalid secret references", func() {
+		cfg := config.ExampleConfig{
+			Spec: config.ExampleConfigSpec{
+				Foo: "bar",
+				SecretRefs: []config.SecretReference{
+					{Name: "api-token"},
+					{Name: ""},
+					{Name: "api-token"},
+				},
+			},
+		}
+		err := validation.Validate(cfg)
+		Expect(err).To(MatchError(ContainSubstring("spec.secretRefs[1].name: Required value")))
+		Expect(err).To(MatchError(ContainSubstring("spec.secretRefs[2].name: Duplicate value")))
+	})
+
+	It("should successfully validate secret references", func() {
+		cfg := config.ExampleConfig{
+			Spec: config.ExampleConfigSpec{
+				Foo: "bar",
+				SecretRefs: []config.SecretReference{
+					{Name: "api-token"},
+					{Name: "ca-bundle"},
+				},
+			},
+		}
+		err := validation.Validate(cfg)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
 	// TODO(user): additional tests
 })
@@ -10,15 +10,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/extensions/pkg/controller/extension"
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/controllerutils"
+	predicateutils "github.com/gardener/gardener/pkg/controllerutils/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	crctrl "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"gardener-extension-example/pkg/sharding"
 )
 
 // ErrInvalidController is an error, which is returned when attempting to create
@@ -56,6 +65,10 @@ type Controller struct {
 	// set up.
 	watchBuilder extensionscontroller.WatchBuilder
 
+	// watches are additional watches on objects related to the extension
+	// resources.
+	watches []watch
+
 	// IgnoreOperationAnnotation specifies whether to ignore the operation
 	// annotation or not.  If the annotation is not ignored, the extension
 	// controller will only reconcile with a present operation annotation
@@ -66,13 +79,39 @@ type Controller struct {
 	// extensionClasses defines the extension classes this extension is
 	// responsible for.
 	extensionClasses []extensionsv1alpha1.ExtensionClass
+
+	// pauseAnnotation is the annotation, which pauses the reconciliation
+	// of a single extension resource. An empty annotation disables
+	// pausing.
+	pauseAnnotation string
+
+	// triggers are predicates, which trigger a reconciliation of an
+	// extension resource, even if it does not pass the other predicates.
+	triggers []predicate.Predicate
+
+	// sharder restricts the controller to the shoot namespaces owned by
+	// the shard of the replica. When nil, sharding is disabled.
+	sharder *sharding.Sharder
+
+	// drainTimeout is the duration for which in-flight reconciliations
+	// may continue after shutdown has been initiated. A zero timeout
+	// cancels them immediately.
+	drainTimeout time.Duration
+
+	// recorder records the calls of the actuator. When nil, the calls are
+	// not recorded.
+	recorder *OperationRecorder
 }
 
 // New creates a new [Controller] with the given options.
 func New(opts ...Option) (*Controller, error) {
 	c := &Controller{
 		predicates:       make([]predicate.Predicate, 0),
+		triggers:         make([]predicate.Predicate, 0),
+		watches:          make([]watch, 0),
 		extensionClasses: make([]extensionsv1alpha1.ExtensionClass, 0),
+		pauseAnnotation:  DefaultPauseAnnotation,
+		drainTimeout:     DefaultDrainTimeout,
 		controllerOptions: crctrl.Options{
 			MaxConcurrentReconciles: 5,
 			ReconciliationTimeout:   controllerutils.DefaultReconciliationTimeout,
@@ -107,30 +146,148 @@ func New(opts ...Option) (*Controller, error) {
 }
 
 // SetupWithManager registers the [Controller] with the given [manager.Manager].
-// Internally, this method uses [extension.Add], which builds a reconciler
-// wrapper around the [extension.Actuator] used by the [Controller].
+// Internally, this method uses [extension.NewReconciler], which builds a
+// reconciler wrapper around the [extension.Actuator] used by the
+// [Controller].
+//
+// Unless pausing is disabled, the actuator is wrapped by
+// [NewPausingActuator], and changes of the pause annotation trigger a
+// reconciliation, so that pausing and resuming take effect immediately.
+// Likewise, events matching any of the trigger predicates configured via
+// [WithTriggerPredicate] trigger a reconciliation. If an [OperationRecorder]
+// is configured, the actuator is finally wrapped by [NewRecordingActuator],
+// and the reconciler by [NewForgettingReconciler].
+//
+// Unless draining is disabled, the reconciler is wrapped by
+// [NewDrainingReconciler], so that in-flight reconciliations are completed on
+// shutdown. If a [sharding.Sharder] is configured, the reconciler is wrapped
+// by [NewShardedReconciler], so that requeued requests in namespaces, which
+// have been handed over to another replica, are dropped.
 func (c *Controller) SetupWithManager(ctx context.Context, mgr manager.Manager) error {
 	if len(c.predicates) == 0 {
 		c.predicates = extension.DefaultPredicates(ctx, mgr, c.ignoreOperationAnnotation)
 	}
 
-	return extension.Add(
+	act := c.actuator
+	triggers := slices.Clone(c.triggers)
+	if c.pauseAnnotation != "" {
+		act = NewPausingActuator(act, mgr.GetClient(), mgr.GetEventRecorder(c.name), c.pauseAnnotation)
+		triggers = append(triggers, PauseAnnotationChanged(c.pauseAnnotation))
+	}
+	if c.recorder != nil {
+		act = NewRecordingActuator(act, c.recorder)
+	}
+
+	predicates := c.predicates
+	if len(triggers) > 0 {
+		predicates = []predicate.Predicate{
+			predicate.Or(append([]predicate.Predicate{predicate.And(c.predicates...)}, triggers...)...),
+		}
+	}
+
+	watchBuilder := slices.Clone(c.watchBuilder)
+	for _, w := range c.watches {
+		if c.sharder != nil {
+			w.mapper = ShardedMapper(c.sharder, w.mapper)
+		}
+		watchBuilder.Register(func(ctrl crctrl.Controller) error {
+			return w.addToController(mgr, ctrl)
+		})
+	}
+
+	// All replicas reconcile the extension resources in the namespaces of
+	// their shard, so the controller does not need leader election.
+	// Resources, which a replica takes over after rebalancing, are replayed
+	// as if the controller had been started.
+	if c.sharder != nil {
+		c.controllerOptions.NeedLeaderElection = new(false)
+		predicates = append([]predicate.Predicate{c.sharder.Predicate()}, predicates...)
+		replayPredicates := append(
+			[]predicate.Predicate{predicateutils.HasType(c.extensionType), predicateutils.HasClass(c.extensionClasses...)},
+			predicates...,
+		)
+		watchBuilder.Register(func(ctrl crctrl.Controller) error {
+			src := source.Channel(
+				c.sharder.Events(),
+				&handler.EnqueueRequestForObject{},
+				source.WithPredicates[client.Object, reconcile.Request](ReplayAsCreate(replayPredicates...)),
+			)
+
+			return ctrl.Watch(src)
+		})
+	}
+
+	return c.add(
 		mgr,
 		extension.AddArgs{
-			Actuator:                  c.actuator,
+			Actuator:                  act,
 			Name:                      c.name,
 			FinalizerSuffix:           c.finalizerSuffix,
 			ControllerOptions:         c.controllerOptions,
-			Predicates:                c.predicates,
+			Predicates:                predicates,
 			Resync:                    c.resync,
 			Type:                      c.extensionType,
-			WatchBuilder:              c.watchBuilder,
+			WatchBuilder:              watchBuilder,
 			IgnoreOperationAnnotation: c.ignoreOperationAnnotation,
 			ExtensionClasses:          c.extensionClasses,
 		},
 	)
 }
 
+// add adds the controller to the given [manager.Manager] like [extension.Add],
+// but wraps the reconciler created via [extension.NewReconciler], so that each
+// request can be checked, before it reaches the reconciler. Like
+// [extension.Add], a zero reconciliation timeout falls back to the
+// [controllerutils.DefaultReconciliationTimeout].
+func (c *Controller) add(mgr manager.Manager, args extension.AddArgs) error {
+	predicates := append(
+		[]predicate.Predicate{predicateutils.HasType(args.Type), predicateutils.HasClass(args.ExtensionClasses...)},
+		args.Predicates...,
+	)
+
+	if args.ControllerOptions.ReconciliationTimeout == 0 {
+		args.ControllerOptions.ReconciliationTimeout = controllerutils.DefaultReconciliationTimeout
+	}
+
+	r := extension.NewReconciler(mgr, args)
+	if c.recorder != nil {
+		r = NewForgettingReconciler(r, mgr.GetClient(), c.recorder)
+	}
+	if c.drainTimeout > 0 {
+		r = NewDrainingReconciler(r, c.drainTimeout)
+	}
+	if c.sharder != nil {
+		r = NewShardedReconciler(r, c.sharder, c.recorder)
+	}
+
+	ctrl, err := builder.
+		ControllerManagedBy(mgr).
+		Named(args.Name).
+		WithOptions(args.ControllerOptions).
+		Watches(
+			&extensionsv1alpha1.Extension{},
+			&handler.EnqueueRequestForObject{},
+			builder.WithPredicates(predicates...),
+		).
+		Build(r)
+	if err != nil {
+		return err
+	}
+
+	if args.IgnoreOperationAnnotation {
+		src := source.Kind[client.Object](
+			mgr.GetCache(),
+			&extensionsv1alpha1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(extension.ClusterToExtensionMapper(mgr.GetClient(), predicates...)),
+		)
+		if err := ctrl.Watch(src); err != nil {
+			return err
+		}
+	}
+
+	return args.WatchBuilder.AddToController(ctrl)
+}
+
 // Option is a function, which configures the [Controller].
 type Option func(c *Controller) error
 
@@ -195,7 +352,8 @@ func WithMaxConcurrentReconciles(val int) Option {
 }
 
 // WithReconciliationTimeout is an [Option], which configures the
-// [Controller] with the given reconciliation timeout duration.
+// [Controller] with the given reconciliation timeout duration. A zero duration
+// falls back to the [controllerutils.DefaultReconciliationTimeout].
 func WithReconciliationTimeout(val time.Duration) Option {
 	opt := func(m *Controller) error {
 		m.controllerOptions.ReconciliationTimeout = val
@@ -218,6 +376,34 @@ func WithPredicate(pred predicate.Predicate) Option {
 	return opt
 }
 
+// WithTriggerPredicate is an [Option], which configures the [Controller] to
+// reconcile extension resources on events matching the given
+// [predicate.Predicate], even if they do not pass the other predicates, e.g.
+// when an annotation requesting a custom operation is added.
+func WithTriggerPredicate(pred predicate.Predicate) Option {
+	opt := func(c *Controller) error {
+		c.triggers = append(c.triggers, pred)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithSharder is an [Option], which configures the [Controller] to reconcile
+// only the extension resources in shoot namespaces owned by the shard of the
+// replica, as determined by the given [sharding.Sharder]. The sharder must be
+// added to the manager separately.
+func WithSharder(s *sharding.Sharder) Option {
+	opt := func(c *Controller) error {
+		c.sharder = s
+
+		return nil
+	}
+
+	return opt
+}
+
 // WithExtensionType is an [Option], which configures the [Controller] to
 // reconcile extension resources of the given type.
 func WithExtensionType(extensionType string) Option {
@@ -277,3 +463,44 @@ func WithResyncInterval(duration time.Duration) Option {
 
 	return opt
 }
+
+// WithPauseAnnotation is an [Option], which configures the [Controller] to
+// pause the reconciliation of extension resources annotated with the given
+// annotation set to "true". An empty annotation disables pausing. By default
+// the [DefaultPauseAnnotation] is used.
+func WithPauseAnnotation(annotation string) Option {
+	opt := func(c *Controller) error {
+		c.pauseAnnotation = annotation
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithDrainTimeout is an [Option], which configures the [Controller] to let
+// in-flight reconciliations continue for the given duration after shutdown
+// has been initiated. A zero timeout disables draining. By default
+// the [DefaultDrainTimeout] is used.
+func WithDrainTimeout(timeout time.Duration) Option {
+	opt := func(c *Controller) error {
+		c.drainTimeout = timeout
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithOperationRecorder is an [Option], which configures the [Controller] to
+// record the calls of the actuator with the given [OperationRecorder], e.g. in
+// order to serve them via debug endpoints.
+func WithOperationRecorder(recorder *OperationRecorder) Option {
+	opt := func(c *Controller) error {
+		c.recorder = recorder
+
+		return nil
+	}
+
+	return opt
+}
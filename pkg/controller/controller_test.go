@@ -6,21 +6,48 @@ package controller_test
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
 	"github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	"github.com/gardener/gardener/pkg/controllerutils"
 	predicateutils "github.com/gardener/gardener/pkg/controllerutils/predicate"
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	crctrl "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	exampleactuator "gardener-extension-example/pkg/actuator/example"
 	"gardener-extension-example/pkg/controller"
 )
 
+// deadlineActuator is an actuator, which reports the deadline of the context
+// of its first reconciliation.
+type deadlineActuator struct {
+	fakeActuator
+
+	once     sync.Once
+	deadline chan time.Time
+}
+
+func (a *deadlineActuator) Reconcile(ctx context.Context, _ logr.Logger, _ *v1alpha1.Extension) error {
+	a.once.Do(func() {
+		deadline, _ := ctx.Deadline()
+		a.deadline <- deadline
+	})
+
+	return nil
+}
+
 var _ = Describe("Controller", Ordered, func() {
 	var act *exampleactuator.Actuator
 
@@ -96,6 +123,11 @@ var _ = Describe("Controller", Ordered, func() {
 			controller.WithResyncInterval(30 * time.Second),
 			controller.WithPredicate(predicateutils.HasName("example")),
 			controller.WithWatchBuilder(extensionscontroller.NewWatchBuilder()),
+			controller.WithWatch(
+				&v1alpha1.Cluster{},
+				controller.ClusterToExtensionsMapper("example"),
+				controller.IgnoreStatusUpdates(),
+			),
 		}
 		c, err := controller.New(opts...)
 
@@ -107,4 +139,58 @@ var _ = Describe("Controller", Ordered, func() {
 		Expect(m).NotTo(BeNil())
 		Expect(c.SetupWithManager(context.TODO(), m)).To(Succeed())
 	})
+
+	It("should fall back to the default reconciliation timeout", func() {
+		inner := &deadlineActuator{deadline: make(chan time.Time, 1)}
+		opts := []controller.Option{
+			controller.WithActuator(inner),
+			controller.WithName("example-timeout"),
+			controller.WithExtensionType("example"),
+			controller.WithExtensionClass(v1alpha1.ExtensionClassShoot),
+			controller.WithReconciliationTimeout(0),
+			controller.WithIgnoreOperationAnnotation(true),
+		}
+		c, err := controller.New(opts...)
+		Expect(err).NotTo(HaveOccurred())
+
+		m, err := manager.New(cfg, manager.Options{
+			Scheme:                 kubernetes.SeedScheme,
+			Metrics:                metricsserver.Options{BindAddress: "0"},
+			HealthProbeBindAddress: "0",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		mgrCtx, mgrCancel := context.WithCancel(ctx)
+		DeferCleanup(mgrCancel)
+		Expect(c.SetupWithManager(mgrCtx, m)).To(Succeed())
+		go func() {
+			defer GinkgoRecover()
+			Expect(m.Start(mgrCtx)).To(Succeed())
+		}()
+
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shoot--local--timeout"}}
+		cluster := &v1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace.Name},
+			Spec: v1alpha1.ClusterSpec{
+				CloudProfile: runtime.RawExtension{Raw: []byte(`{}`)},
+				Seed:         runtime.RawExtension{Raw: []byte(`{}`)},
+				Shoot:        runtime.RawExtension{Raw: []byte(`{"apiVersion":"core.gardener.cloud/v1beta1","kind":"Shoot"}`)},
+			},
+		}
+		ex := &v1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: namespace.Name},
+			Spec: v1alpha1.ExtensionSpec{
+				DefaultSpec: v1alpha1.DefaultSpec{Type: "example"},
+			},
+		}
+		for _, obj := range []client.Object{namespace, cluster, ex} {
+			Expect(m.GetClient().Create(ctx, obj)).To(Succeed())
+		}
+
+		// Actuator calls must not run without a timeout
+		start := time.Now()
+		var deadline time.Time
+		Eventually(inner.deadline).WithTimeout(10 * time.Second).Should(Receive(&deadline))
+		Expect(deadline).To(BeTemporally("~", start.Add(controllerutils.DefaultReconciliationTimeout), 10*time.Second))
+	})
 })
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultDrainTimeout is the default duration for which in-flight
+// reconciliations may continue after shutdown has been initiated. It should be
+// lower than the graceful shutdown timeout of the manager.
+const DefaultDrainTimeout = 20 * time.Second
+
+// drainingReconciler is a [reconcile.Reconciler], which lets in-flight
+// reconciliations of the wrapped reconciler complete on shutdown.
+type drainingReconciler struct {
+	reconcile.Reconciler
+
+	timeout time.Duration
+}
+
+// NewDrainingReconciler returns a new [reconcile.Reconciler], which wraps the
+// given reconciler and lets its in-flight reconciliations complete on
+// shutdown.
+//
+// On shutdown the controller stops accepting new reconciliations and cancels
+// the context of the in-flight ones, which would abort the actuator half-way
+// and fail to record the outcome in the status of the extension resource or to
+// remove its finalizer. Instead, the wrapped reconciler is called with a
+// context, which is canceled only once the given drain timeout has passed since
+// shutdown was initiated. The deadline of the original context, i.e. the
+// reconciliation timeout, is retained. Since the manager waits for the
+// in-flight reconciliations before releasing the leader election lease, the
+// next leader takes over only once the reconciliations have been completed or
+// the timeout has passed.
+func NewDrainingReconciler(r reconcile.Reconciler, timeout time.Duration) reconcile.Reconciler {
+	dr := &drainingReconciler{
+		Reconciler: r,
+		timeout:    timeout,
+	}
+
+	return dr
+}
+
+// Reconcile reconciles the given [reconcile.Request] with a context, which is
+// detached from shutdown for the drain timeout. This method implements the
+// [reconcile.Reconciler] interface.
+func (r *drainingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := r.detach(ctx, ctrllog.FromContext(ctx))
+	defer cancel()
+
+	return r.Reconciler.Reconcile(ctx, req)
+}
+
+// detach returns a context, which retains the values and the deadline of the
+// given context, but is canceled only once the drain timeout has passed since
+// the given context has been canceled.
+func (r *drainingReconciler) detach(ctx context.Context, logger logr.Logger) (context.Context, context.CancelFunc) {
+	base := context.WithoutCancel(ctx)
+	cancelDeadline := context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		base, cancelDeadline = context.WithDeadline(base, deadline)
+	}
+
+	detached, cancel := context.WithCancel(base)
+	stop := context.AfterFunc(ctx, func() {
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			return
+		}
+
+		logger.Info("waiting for in-flight reconciliation to complete before shutdown", "timeout", r.timeout)
+		timer := time.NewTimer(r.timeout)
+		defer timer.Stop()
+
+		select {
+		case <-detached.Done():
+		case <-timer.C:
+			logger.Info("canceling in-flight reconciliation, which did not complete in time")
+			cancel()
+		}
+	})
+
+	return detached, func() {
+		stop()
+		cancel()
+		cancelDeadline()
+	}
+}
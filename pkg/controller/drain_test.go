@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/extension"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"gardener-extension-example/pkg/controller"
+)
+
+// blockingActuator is an actuator, which blocks reconciliations until they
+// are released or their context is canceled.
+type blockingActuator struct {
+	fakeActuator
+
+	started  chan struct{}
+	release  chan struct{}
+	deadline time.Time
+}
+
+func (a *blockingActuator) Reconcile(ctx context.Context, _ logr.Logger, _ *extensionsv1alpha1.Extension) error {
+	a.deadline, _ = ctx.Deadline()
+	close(a.started)
+
+	select {
+	case <-a.release:
+		a.operations = append(a.operations, "reconcile")
+
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fakeManager is a [manager.Manager], which only provides the given client.
+type fakeManager struct {
+	manager.Manager
+
+	client client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client {
+	return m.client
+}
+
+func (m *fakeManager) GetAPIReader() client.Reader {
+	return m.client
+}
+
+// failCanceled returns [interceptor.Funcs], which fail requests with a
+// canceled context like a client talking to an API server does.
+func failCanceled() interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			return c.Get(ctx, key, obj, opts...)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+		SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+		},
+	}
+}
+
+var _ = Describe("Drain", func() {
+	var (
+		fakeClient client.Client
+		inner      *blockingActuator
+		ex         *extensionsv1alpha1.Extension
+		req        reconcile.Request
+	)
+
+	// reconcileInBackground reconciles the extension resource with the given
+	// reconciler in the background and returns a channel for the result.
+	reconcileInBackground := func(ctx context.Context, r reconcile.Reconciler) <-chan error {
+		result := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			_, err := r.Reconcile(ctx, req)
+			result <- err
+		}()
+		Eventually(inner.started).Should(BeClosed())
+
+		return result
+	}
+
+	BeforeEach(func() {
+		inner = &blockingActuator{
+			started: make(chan struct{}),
+			release: make(chan struct{}),
+		}
+		ex = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "shoot--local--local",
+			},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type: "example",
+				},
+			},
+		}
+		req = reconcile.Request{NamespacedName: types.NamespacedName{Name: ex.Name, Namespace: ex.Namespace}}
+
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: ex.Namespace,
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(kubernetes.SeedScheme).
+			WithObjects(namespace, ex).
+			WithStatusSubresource(&extensionsv1alpha1.Extension{}).
+			WithInterceptorFuncs(failCanceled()).
+			Build()
+	})
+
+	// newReconciler returns the reconciler of the extension resource, which
+	// wraps the blocking actuator.
+	newReconciler := func() reconcile.Reconciler {
+		return extension.NewReconciler(
+			&fakeManager{client: fakeClient},
+			extension.AddArgs{Actuator: inner, FinalizerSuffix: "example"},
+		)
+	}
+
+	It("should let in-flight reconciliations complete after shutdown", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		result := reconcileInBackground(ctx, controller.NewDrainingReconciler(newReconciler(), time.Minute))
+
+		cancel()
+		Consistently(result, 100*time.Millisecond).ShouldNot(Receive())
+
+		close(inner.release)
+		Eventually(result).Should(Receive(BeNil()))
+		Expect(inner.operations).To(ConsistOf("reconcile"))
+
+		// The outcome is recorded in the status, and the finalizer is kept
+		Expect(fakeClient.Get(context.Background(), req.NamespacedName, ex)).To(Succeed())
+		Expect(ex.Status.LastOperation).NotTo(BeNil())
+		Expect(ex.Status.LastOperation.State).To(Equal(gardencorev1beta1.LastOperationStateSucceeded))
+		Expect(ex.Finalizers).To(ConsistOf(extension.FinalizerPrefix + "/example"))
+	})
+
+	It("should cancel in-flight reconciliations once the drain timeout has passed", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		result := reconcileInBackground(ctx, controller.NewDrainingReconciler(newReconciler(), 100*time.Millisecond))
+
+		cancel()
+		Eventually(result).Should(Receive(MatchError(context.Canceled)))
+		Expect(inner.operations).To(BeEmpty())
+	})
+
+	It("should retain the deadline of the original context", func() {
+		deadline := time.Now().Add(200 * time.Millisecond)
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		defer cancel()
+		result := reconcileInBackground(ctx, controller.NewDrainingReconciler(newReconciler(), time.Minute))
+
+		Eventually(result).Should(Receive(MatchError(context.DeadlineExceeded)))
+		Expect(inner.deadline).To(BeTemporally("==", deadline))
+	})
+
+	It("should cancel in-flight reconciliations immediately without draining", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		result := reconcileInBackground(ctx, newReconciler())
+
+		cancel()
+		Eventually(result).Should(Receive(MatchError(context.Canceled)))
+
+		// The status still reports the operation as processing
+		Expect(fakeClient.Get(context.Background(), req.NamespacedName, ex)).To(Succeed())
+		Expect(ex.Status.LastOperation).NotTo(BeNil())
+		Expect(ex.Status.LastOperation.State).To(Equal(gardencorev1beta1.LastOperationStateProcessing))
+	})
+})
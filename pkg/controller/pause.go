@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/extension"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// DefaultPauseAnnotation is the default annotation, which pauses the
+	// reconciliation of a single [extensionsv1alpha1.Extension] resource,
+	// when set to "true".
+	DefaultPauseAnnotation = "example.extensions.gardener.cloud/paused"
+
+	// ConditionTypePaused is the type of the condition, which reports
+	// whether the reconciliation of an [extensionsv1alpha1.Extension]
+	// resource is paused.
+	ConditionTypePaused gardencorev1beta1.ConditionType = "Paused"
+
+	// EventReasonPaused is the reason of the event, which is emitted when
+	// an [extensionsv1alpha1.Extension] resource is paused.
+	EventReasonPaused = "Paused"
+
+	// EventReasonResumed is the reason of the event, which is emitted when
+	// an [extensionsv1alpha1.Extension] resource is resumed.
+	EventReasonResumed = "Resumed"
+)
+
+// IsPaused returns true, if the given object is paused via the given
+// annotation.
+func IsPaused(obj metav1.Object, annotation string) bool {
+	if annotation == "" {
+		return false
+	}
+
+	paused, err := strconv.ParseBool(obj.GetAnnotations()[annotation])
+
+	return err == nil && paused
+}
+
+// PauseAnnotationChanged returns a [predicate.Predicate], which matches updates
+// of objects, which change whether the object is paused via the given
+// annotation, so that pausing and resuming takes effect immediately.
+func PauseAnnotationChanged(annotation string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+
+			return IsPaused(e.ObjectOld, annotation) != IsPaused(e.ObjectNew, annotation)
+		},
+	}
+}
+
+// pausingActuator is an [extension.Actuator], which suspends the wrapped
+// actuator for [extensionsv1alpha1.Extension] resources paused via an
+// annotation.
+type pausingActuator struct {
+	extension.Actuator
+
+	client     client.Client
+	recorder   events.EventRecorder
+	annotation string
+	clock      clock.Clock
+}
+
+// NewPausingActuator returns a new [extension.Actuator], which wraps the given
+// actuator and suspends it for [extensionsv1alpha1.Extension] resources, which
+// are paused via the given annotation.
+//
+// Reconciling, deleting, restoring and migrating a paused resource is a no-op,
+// so that the finalizer is still handled by the reconciler and neither the
+// deletion of the shoot nor the migration of its control plane hangs, which
+// matches deletions in dry-run mode. Objects left behind by a skipped deletion
+// are removed by the garbage collector, which must therefore be enabled.
+// Forceful deletion is not suspended. Pausing and resuming is reported via the
+// [ConditionTypePaused] condition and events.
+func NewPausingActuator(act extension.Actuator, c client.Client, recorder events.EventRecorder, annotation string) extension.Actuator {
+	a := &pausingActuator{
+		Actuator:   act,
+		client:     c,
+		recorder:   recorder,
+		annotation: annotation,
+		clock:      clock.RealClock{},
+	}
+
+	return a
+}
+
+// Reconcile reconciles the given [extensionsv1alpha1.Extension] resource,
+// unless it is paused. This method implements the [extension.Actuator]
+// interface.
+func (a *pausingActuator) Reconcile(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	paused, err := a.syncPaused(ctx, logger, ex)
+	if err != nil {
+		return err
+	}
+
+	if paused {
+		return a.skipped(logger, "reconciliation")
+	}
+
+	return a.Actuator.Reconcile(ctx, logger, ex)
+}
+
+// Delete deletes the given [extensionsv1alpha1.Extension] resource, unless it
+// is paused, in which case only the finalizer is removed by the reconciler.
+// This method implements the [extension.Actuator] interface.
+func (a *pausingActuator) Delete(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	paused, err := a.syncPaused(ctx, logger, ex)
+	if err != nil {
+		return err
+	}
+
+	if paused {
+		return a.skipped(logger, "deletion")
+	}
+
+	return a.Actuator.Delete(ctx, logger, ex)
+}
+
+// Restore restores the given [extensionsv1alpha1.Extension] resource, unless
+// it is paused. This method implements the [extension.Actuator] interface.
+func (a *pausingActuator) Restore(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	paused, err := a.syncPaused(ctx, logger, ex)
+	if err != nil {
+		return err
+	}
+
+	if paused {
+		return a.skipped(logger, "restoration")
+	}
+
+	return a.Actuator.Restore(ctx, logger, ex)
+}
+
+// Migrate migrates the given [extensionsv1alpha1.Extension] resource, unless
+// it is paused. This method implements the [extension.Actuator] interface.
+func (a *pausingActuator) Migrate(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	paused, err := a.syncPaused(ctx, logger, ex)
+	if err != nil {
+		return err
+	}
+
+	if paused {
+		return a.skipped(logger, "migration")
+	}
+
+	return a.Actuator.Migrate(ctx, logger, ex)
+}
+
+// skipped logs that the given operation on a paused resource is skipped.
+func (a *pausingActuator) skipped(logger logr.Logger, operation string) error {
+	logger.Info("skipping "+operation+" of paused extension", "annotation", a.annotation)
+
+	return nil
+}
+
+// syncPaused returns whether the given [extensionsv1alpha1.Extension] resource
+// is paused and updates its [ConditionTypePaused] condition, when it has been
+// paused or resumed since the last reconciliation.
+func (a *pausingActuator) syncPaused(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) (bool, error) {
+	paused := IsPaused(ex, a.annotation)
+	current := v1beta1helper.GetCondition(ex.Status.Conditions, ConditionTypePaused)
+
+	var (
+		status  gardencorev1beta1.ConditionStatus
+		reason  string
+		action  string
+		message string
+	)
+
+	switch {
+	case paused && (current == nil || current.Status != gardencorev1beta1.ConditionTrue):
+		status = gardencorev1beta1.ConditionTrue
+		reason = EventReasonPaused
+		action = "Pause"
+		message = fmt.Sprintf("Reconciliation is paused via annotation %s", a.annotation)
+	case !paused && current != nil && current.Status == gardencorev1beta1.ConditionTrue:
+		status = gardencorev1beta1.ConditionFalse
+		reason = EventReasonResumed
+		action = "Resume"
+		message = fmt.Sprintf("Reconciliation has been resumed after annotation %s was removed", a.annotation)
+	default:
+		return paused, nil
+	}
+
+	condition := v1beta1helper.GetOrInitConditionWithClock(a.clock, ex.Status.Conditions, ConditionTypePaused)
+	condition = v1beta1helper.UpdatedConditionWithClock(a.clock, condition, status, reason, message)
+
+	patch := client.MergeFrom(ex.DeepCopy())
+	ex.Status.Conditions = v1beta1helper.MergeConditions(ex.Status.Conditions, condition)
+	if err := a.client.Status().Patch(ctx, ex, patch); err != nil {
+		return false, fmt.Errorf("failed to update %s condition: %w", ConditionTypePaused, err)
+	}
+
+	logger.Info(message)
+	if a.recorder != nil {
+		a.recorder.Eventf(ex, nil, corev1.EventTypeNormal, reason, action, "%s", message)
+	}
+
+	return paused, nil
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller_test
+
+import (
+	"context"
+
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"gardener-extension-example/pkg/controller"
+)
+
+// fakeActuator is an actuator, which records the operations it performed.
+type fakeActuator struct {
+	operations []string
+}
+
+func (a *fakeActuator) Reconcile(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	a.operations = append(a.operations, "reconcile")
+
+	return nil
+}
+
+func (a *fakeActuator) Delete(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	a.operations = append(a.operations, "delete")
+
+	return nil
+}
+
+func (a *fakeActuator) ForceDelete(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	a.operations = append(a.operations, "force-delete")
+
+	return nil
+}
+
+func (a *fakeActuator) Restore(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	a.operations = append(a.operations, "restore")
+
+	return nil
+}
+
+func (a *fakeActuator) Migrate(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	a.operations = append(a.operations, "migrate")
+
+	return nil
+}
+
+var _ = Describe("Pause", func() {
+	var (
+		ctx        = context.Background()
+		fakeClient client.Client
+		recorder   *events.FakeRecorder
+		inner      *fakeActuator
+		ex         *extensionsv1alpha1.Extension
+	)
+
+	// setPaused sets the pause annotation of the extension resource.
+	setPaused := func(paused string) {
+		patch := client.MergeFrom(ex.DeepCopy())
+		metav1.SetMetaDataAnnotation(&ex.ObjectMeta, controller.DefaultPauseAnnotation, paused)
+		Expect(fakeClient.Patch(ctx, ex, patch)).To(Succeed())
+	}
+
+	// getPausedCondition returns the Paused condition of the extension
+	// resource.
+	getPausedCondition := func() *gardencorev1beta1.Condition {
+		current := &extensionsv1alpha1.Extension{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(ex), current)).To(Succeed())
+
+		return v1beta1helper.GetCondition(current.Status.Conditions, controller.ConditionTypePaused)
+	}
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(s)).To(Succeed())
+
+		ex = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "shoot--local--local",
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(ex).
+			WithStatusSubresource(ex).
+			Build()
+		recorder = events.NewFakeRecorder(10)
+		inner = &fakeActuator{}
+	})
+
+	It("should detect paused objects", func() {
+		Expect(controller.IsPaused(ex, controller.DefaultPauseAnnotation)).To(BeFalse())
+
+		metav1.SetMetaDataAnnotation(&ex.ObjectMeta, controller.DefaultPauseAnnotation, "true")
+		Expect(controller.IsPaused(ex, controller.DefaultPauseAnnotation)).To(BeTrue())
+		Expect(controller.IsPaused(ex, "")).To(BeFalse())
+
+		metav1.SetMetaDataAnnotation(&ex.ObjectMeta, controller.DefaultPauseAnnotation, "invalid")
+		Expect(controller.IsPaused(ex, controller.DefaultPauseAnnotation)).To(BeFalse())
+	})
+
+	It("should trigger reconciliation, when the pause annotation changes", func() {
+		pred := controller.PauseAnnotationChanged(controller.DefaultPauseAnnotation)
+		paused := ex.DeepCopy()
+		metav1.SetMetaDataAnnotation(&paused.ObjectMeta, controller.DefaultPauseAnnotation, "true")
+
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: ex, ObjectNew: paused})).To(BeTrue())
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: paused, ObjectNew: ex})).To(BeTrue())
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: ex, ObjectNew: ex})).To(BeFalse())
+		Expect(pred.Create(event.CreateEvent{Object: paused})).To(BeFalse())
+	})
+
+	It("should pass through operations on objects, which are not paused", func() {
+		act := controller.NewPausingActuator(inner, fakeClient, recorder, controller.DefaultPauseAnnotation)
+
+		Expect(act.Reconcile(ctx, logr.Discard(), ex)).To(Succeed())
+		Expect(act.Delete(ctx, logr.Discard(), ex)).To(Succeed())
+		Expect(inner.operations).To(Equal([]string{"reconcile", "delete"}))
+		Expect(getPausedCondition()).To(BeNil())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should suspend operations on paused objects and resume them", func() {
+		act := controller.NewPausingActuator(inner, fakeClient, recorder, controller.DefaultPauseAnnotation)
+
+		// Pause the extension resource
+		setPaused("true")
+		Expect(act.Reconcile(ctx, logr.Discard(), ex)).To(Succeed())
+		Expect(inner.operations).To(BeEmpty())
+		condition := getPausedCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(gardencorev1beta1.ConditionTrue))
+		Expect(recorder.Events).To(Receive(ContainSubstring(controller.EventReasonPaused)))
+
+		// Deletion, restoration and migration are skipped without
+		// blocking the finalizer handling, but forceful deletion is not.
+		for _, op := range []func(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error{act.Delete, act.Restore, act.Migrate} {
+			Expect(op(ctx, logr.Discard(), ex)).To(Succeed())
+		}
+		Expect(act.ForceDelete(ctx, logr.Discard(), ex)).To(Succeed())
+		Expect(inner.operations).To(Equal([]string{"force-delete"}))
+
+		// No further events are emitted, while the resource stays paused
+		Expect(recorder.Events).To(BeEmpty())
+
+		// Resume the extension resource
+		setPaused("false")
+		Expect(act.Reconcile(ctx, logr.Discard(), ex)).To(Succeed())
+		Expect(inner.operations).To(Equal([]string{"force-delete", "reconcile"}))
+		condition = getPausedCondition()
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(gardencorev1beta1.ConditionFalse))
+		Expect(recorder.Events).To(Receive(ContainSubstring(controller.EventReasonResumed)))
+	})
+
+	It("should create a controller with pausing disabled", func() {
+		c, err := controller.New(
+			controller.WithActuator(inner),
+			controller.WithName("example"),
+			controller.WithExtensionType("example"),
+			controller.WithExtensionClass(extensionsv1alpha1.ExtensionClassShoot),
+			controller.WithPauseAnnotation(""),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).NotTo(BeNil())
+	})
+})
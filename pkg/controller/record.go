@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gardener/gardener/extensions/pkg/controller/extension"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// OperationResultSucceeded is the result of an operation, which
+	// completed without an error.
+	OperationResultSucceeded = "Succeeded"
+
+	// OperationResultFailed is the result of an operation, which failed.
+	OperationResultFailed = "Failed"
+)
+
+// OperationRecord describes the last call of an [extension.Actuator] for an
+// [extensionsv1alpha1.Extension] resource.
+type OperationRecord struct {
+	// Operation is the name of the actuator method, e.g. "Reconcile".
+	Operation string `json:"operation"`
+
+	// Time is the time, at which the operation was started.
+	Time metav1.Time `json:"time"`
+
+	// Duration is the duration of the operation.
+	Duration metav1.Duration `json:"duration"`
+
+	// Result is either [OperationResultSucceeded] or
+	// [OperationResultFailed].
+	Result string `json:"result"`
+
+	// Error is the error returned by the operation, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// OperationRecorder remembers the last [OperationRecord] of each
+// [extensionsv1alpha1.Extension] resource handled by this replica. The records
+// are kept in memory only, and are dropped once the extension resource has been
+// deleted or migrated successfully, see [NewRecordingActuator], once it no
+// longer exists, see [NewForgettingReconciler], or once it is no longer owned
+// by the shard of the replica, see [NewShardedReconciler].
+type OperationRecorder struct {
+	clock clock.PassiveClock
+
+	mu      sync.RWMutex
+	records map[client.ObjectKey]OperationRecord
+}
+
+// NewOperationRecorder returns a new, empty [OperationRecorder].
+func NewOperationRecorder() *OperationRecorder {
+	r := &OperationRecorder{
+		clock:   clock.RealClock{},
+		records: make(map[client.ObjectKey]OperationRecord),
+	}
+
+	return r
+}
+
+// Get returns the last [OperationRecord] of the [extensionsv1alpha1.Extension]
+// resource with the given key, if any.
+func (r *OperationRecorder) Get(key client.ObjectKey) (OperationRecord, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.records[key]
+
+	return record, ok
+}
+
+// record remembers the given [OperationRecord] for the
+// [extensionsv1alpha1.Extension] resource with the given key.
+func (r *OperationRecorder) record(key client.ObjectKey, record OperationRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[key] = record
+}
+
+// forget drops the [OperationRecord] of the [extensionsv1alpha1.Extension]
+// resource with the given key.
+func (r *OperationRecorder) forget(key client.ObjectKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, key)
+}
+
+// recordingActuator is an [extension.Actuator], which records the calls of the
+// wrapped actuator with an [OperationRecorder].
+type recordingActuator struct {
+	extension.Actuator
+
+	recorder *OperationRecorder
+}
+
+// NewRecordingActuator returns a new [extension.Actuator], which wraps the
+// given actuator and records the time, duration and result of its calls with
+// the given [OperationRecorder]. The records of extension resources, which
+// have been deleted or migrated successfully, are dropped.
+func NewRecordingActuator(act extension.Actuator, recorder *OperationRecorder) extension.Actuator {
+	a := &recordingActuator{
+		Actuator: act,
+		recorder: recorder,
+	}
+
+	return a
+}
+
+// Reconcile reconciles the given [extensionsv1alpha1.Extension] resource. This
+// method implements the [extension.Actuator] interface.
+func (a *recordingActuator) Reconcile(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	return a.run("Reconcile", ex, false, func() error {
+		return a.Actuator.Reconcile(ctx, logger, ex)
+	})
+}
+
+// Delete deletes the given [extensionsv1alpha1.Extension] resource. This method
+// implements the [extension.Actuator] interface.
+func (a *recordingActuator) Delete(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	return a.run("Delete", ex, true, func() error {
+		return a.Actuator.Delete(ctx, logger, ex)
+	})
+}
+
+// ForceDelete forcefully deletes the given [extensionsv1alpha1.Extension]
+// resource. This method implements the [extension.Actuator] interface.
+func (a *recordingActuator) ForceDelete(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	return a.run("ForceDelete", ex, true, func() error {
+		return a.Actuator.ForceDelete(ctx, logger, ex)
+	})
+}
+
+// Restore restores the given [extensionsv1alpha1.Extension] resource. This
+// method implements the [extension.Actuator] interface.
+func (a *recordingActuator) Restore(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	return a.run("Restore", ex, false, func() error {
+		return a.Actuator.Restore(ctx, logger, ex)
+	})
+}
+
+// Migrate migrates the given [extensionsv1alpha1.Extension] resource. This
+// method implements the [extension.Actuator] interface.
+func (a *recordingActuator) Migrate(ctx context.Context, logger logr.Logger, ex *extensionsv1alpha1.Extension) error {
+	return a.run("Migrate", ex, true, func() error {
+		return a.Actuator.Migrate(ctx, logger, ex)
+	})
+}
+
+// run calls the given function and records the operation with the given name
+// for the given [extensionsv1alpha1.Extension] resource. If forget is true,
+// the record is dropped instead, when the operation succeeds.
+func (a *recordingActuator) run(operation string, ex *extensionsv1alpha1.Extension, forget bool, f func() error) error {
+	key := client.ObjectKeyFromObject(ex)
+	start := a.recorder.clock.Now()
+	err := f()
+
+	if err == nil && forget {
+		a.recorder.forget(key)
+
+		return nil
+	}
+
+	record := OperationRecord{
+		Operation: operation,
+		Time:      metav1.NewTime(start),
+		Duration:  metav1.Duration{Duration: a.recorder.clock.Since(start)},
+		Result:    OperationResultSucceeded,
+	}
+	if err != nil {
+		record.Result = OperationResultFailed
+		record.Error = err.Error()
+	}
+	a.recorder.record(key, record)
+
+	return err
+}
+
+// forgettingReconciler is a [reconcile.Reconciler], which drops the records of
+// [extensionsv1alpha1.Extension] resources, which no longer exist.
+type forgettingReconciler struct {
+	reconcile.Reconciler
+
+	reader   client.Reader
+	recorder *OperationRecorder
+}
+
+// NewForgettingReconciler returns a new [reconcile.Reconciler], which wraps the
+// given reconciler and drops the records of the given [OperationRecorder] for
+// [extensionsv1alpha1.Extension] resources, which are not found via the given
+// [client.Reader], e.g. because they have been deleted without a successful
+// call of the actuator by this replica.
+func NewForgettingReconciler(r reconcile.Reconciler, reader client.Reader, recorder *OperationRecorder) reconcile.Reconciler {
+	fr := &forgettingReconciler{
+		Reconciler: r,
+		reader:     reader,
+		recorder:   recorder,
+	}
+
+	return fr
+}
+
+// Reconcile reconciles the given [reconcile.Request] and drops the record of
+// its [extensionsv1alpha1.Extension] resource, if it no longer exists. This
+// method implements the [reconcile.Reconciler] interface.
+func (r *forgettingReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if err := r.reader.Get(ctx, req.NamespacedName, &extensionsv1alpha1.Extension{}); apierrors.IsNotFound(err) {
+		r.recorder.forget(req.NamespacedName)
+	}
+
+	return r.Reconciler.Reconcile(ctx, req)
+}
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"gardener-extension-example/pkg/controller"
+)
+
+// failingActuator is an actuator, whose reconciliations and deletions fail
+// with the given error.
+type failingActuator struct {
+	fakeActuator
+
+	err error
+}
+
+func (a *failingActuator) Reconcile(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	return a.err
+}
+
+func (a *failingActuator) Delete(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	return a.err
+}
+
+var _ = Describe("Record", func() {
+	var (
+		ctx      = context.Background()
+		recorder *controller.OperationRecorder
+		inner    *failingActuator
+		ex       *extensionsv1alpha1.Extension
+		key      client.ObjectKey
+	)
+
+	BeforeEach(func() {
+		recorder = controller.NewOperationRecorder()
+		inner = &failingActuator{}
+		ex = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: "shoot--local--local",
+			},
+		}
+		key = client.ObjectKeyFromObject(ex)
+	})
+
+	It("should record successful operations", func() {
+		act := controller.NewRecordingActuator(inner, recorder)
+		Expect(act.Reconcile(ctx, logr.Discard(), ex)).To(Succeed())
+
+		record, ok := recorder.Get(key)
+		Expect(ok).To(BeTrue())
+		Expect(record.Operation).To(Equal("Reconcile"))
+		Expect(record.Result).To(Equal(controller.OperationResultSucceeded))
+		Expect(record.Error).To(BeEmpty())
+		Expect(record.Time.Time).To(BeTemporally("~", time.Now(), time.Second))
+		Expect(record.Duration.Duration).To(BeNumerically(">=", 0))
+	})
+
+	It("should record failed operations", func() {
+		inner.err = errors.New("boom")
+		act := controller.NewRecordingActuator(inner, recorder)
+		Expect(act.Reconcile(ctx, logr.Discard(), ex)).To(MatchError("boom"))
+
+		record, ok := recorder.Get(key)
+		Expect(ok).To(BeTrue())
+		Expect(record.Result).To(Equal(controller.OperationResultFailed))
+		Expect(record.Error).To(Equal("boom"))
+	})
+
+	It("should drop the record once the extension has been deleted", func() {
+		act := controller.NewRecordingActuator(inner, recorder)
+		Expect(act.Reconcile(ctx, logr.Discard(), ex)).To(Succeed())
+
+		inner.err = errors.New("boom")
+		Expect(act.Delete(ctx, logr.Discard(), ex)).To(MatchError("boom"))
+		record, ok := recorder.Get(key)
+		Expect(ok).To(BeTrue())
+		Expect(record.Operation).To(Equal("Delete"))
+
+		inner.err = nil
+		Expect(act.Delete(ctx, logr.Discard(), ex)).To(Succeed())
+		_, ok = recorder.Get(key)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should drop the records of extensions, which no longer exist", func() {
+		act := controller.NewRecordingActuator(inner, recorder)
+		Expect(act.Reconcile(ctx, logr.Discard(), ex)).To(Succeed())
+
+		fakeClient := fake.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithObjects(ex).Build()
+		r := controller.NewForgettingReconciler(&countingReconciler{}, fakeClient, recorder)
+		req := reconcile.Request{NamespacedName: key}
+
+		Expect(r.Reconcile(ctx, req)).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+		_, ok := recorder.Get(key)
+		Expect(ok).To(BeTrue())
+
+		Expect(fakeClient.Delete(ctx, ex)).To(Succeed())
+		Expect(r.Reconcile(ctx, req)).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+		_, ok = recorder.Get(key)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not record operations of other extensions", func() {
+		_, ok := recorder.Get(client.ObjectKey{Namespace: "foo", Name: "bar"})
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"gardener-extension-example/pkg/sharding"
+)
+
+// shardedReconciler is a [reconcile.Reconciler], which reconciles only the
+// requests in shoot namespaces owned by the shard of the replica.
+type shardedReconciler struct {
+	reconcile.Reconciler
+
+	sharder  *sharding.Sharder
+	recorder *OperationRecorder
+}
+
+// NewShardedReconciler returns a new [reconcile.Reconciler], which wraps the
+// given reconciler and drops the requests in shoot namespaces, which are not
+// owned by the shard of the replica.
+//
+// The predicates and mappers configured via [WithSharder] filter the events,
+// but requeued requests, e.g. after the resync interval or with backoff after
+// an error, do not pass through them. Without checking the ownership on each
+// request, the previous owner would continue reconciling a namespace after
+// rebalancing. The namespace is handed over to the next owner only once the
+// in-flight reconciliation has completed, see [sharding.Sharder.Acquire].
+//
+// If an [OperationRecorder] is given, the records of the dropped requests are
+// dropped as well, since the resources are handled by another replica.
+func NewShardedReconciler(r reconcile.Reconciler, sharder *sharding.Sharder, recorder *OperationRecorder) reconcile.Reconciler {
+	sr := &shardedReconciler{
+		Reconciler: r,
+		sharder:    sharder,
+		recorder:   recorder,
+	}
+
+	return sr
+}
+
+// Reconcile reconciles the given [reconcile.Request], if its namespace is
+// owned by the shard of the replica. This method implements the
+// [reconcile.Reconciler] interface.
+func (r *shardedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if !r.sharder.Acquire(req.Namespace) {
+		ctrllog.FromContext(ctx).V(1).Info("skipping request in namespace not owned by the shard")
+		if r.recorder != nil {
+			r.recorder.forget(req.NamespacedName)
+		}
+
+		return reconcile.Result{}, nil
+	}
+	defer r.sharder.Done(req.Namespace)
+
+	return r.Reconciler.Reconcile(ctx, req)
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller_test
+
+import (
+	"context"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"gardener-extension-example/pkg/controller"
+	"gardener-extension-example/pkg/sharding"
+)
+
+// countingReconciler is a [reconcile.Reconciler], which counts the reconciled
+// requests and requeues them after the resync interval.
+type countingReconciler struct {
+	count int
+}
+
+func (r *countingReconciler) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	r.count++
+
+	return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+var _ = Describe("Shard", func() {
+	var (
+		sharder *sharding.Sharder
+		inner   *countingReconciler
+		req     = reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "example", Namespace: "shoot--local--local"},
+		}
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(s)).To(Succeed())
+		Expect(coordinationv1.AddToScheme(s)).To(Succeed())
+
+		var err error
+		sharder, err = sharding.New(
+			fake.NewClientBuilder().WithScheme(s).Build(),
+			sharding.WithGroup("gardener-extension-example"),
+			sharding.WithLeaseNamespace("extension-example"),
+			sharding.WithIdentity("replica-a"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		inner = &countingReconciler{}
+	})
+
+	It("should only reconcile requests in namespaces owned by the shard", func() {
+		r := controller.NewShardedReconciler(inner, sharder, nil)
+
+		// Nothing is owned before the first sync
+		Expect(r.Reconcile(context.Background(), req)).To(Equal(reconcile.Result{}))
+		Expect(inner.count).To(Equal(0))
+
+		Expect(sharder.Sync(context.Background())).To(Succeed())
+		Expect(r.Reconcile(context.Background(), req)).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+		Expect(inner.count).To(Equal(1))
+
+		// Requeued requests are dropped without requeueing them once the
+		// namespace is no longer owned
+		Expect(sharder.Release(context.Background())).To(Succeed())
+		Expect(r.Reconcile(context.Background(), req)).To(Equal(reconcile.Result{}))
+		Expect(inner.count).To(Equal(1))
+	})
+
+	It("should drop the records of requests in namespaces not owned by the shard", func() {
+		recorder := controller.NewOperationRecorder()
+		r := controller.NewShardedReconciler(inner, sharder, recorder)
+
+		ex := &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace},
+		}
+		act := controller.NewRecordingActuator(&fakeActuator{}, recorder)
+		Expect(act.Reconcile(context.Background(), logr.Discard(), ex)).To(Succeed())
+
+		Expect(sharder.Sync(context.Background())).To(Succeed())
+		Expect(r.Reconcile(context.Background(), req)).To(Equal(reconcile.Result{RequeueAfter: 30 * time.Second}))
+		_, ok := recorder.Get(req.NamespacedName)
+		Expect(ok).To(BeTrue())
+
+		Expect(sharder.Release(context.Background())).To(Succeed())
+		Expect(r.Reconcile(context.Background(), req)).To(Equal(reconcile.Result{}))
+		_, ok = recorder.Get(req.NamespacedName)
+		Expect(ok).To(BeFalse())
+	})
+})
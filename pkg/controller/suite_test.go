@@ -6,6 +6,7 @@ package controller_test
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -41,6 +42,10 @@ var _ = BeforeSuite(func() {
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
 		Scheme: scheme.Scheme,
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "test", "manifests", "crd", "extensions.gardener.cloud", "v1alpha1"),
+		},
+		ErrorIfCRDPathMissing: true,
 	}
 
 	var err error
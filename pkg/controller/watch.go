@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crctrl "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"gardener-extension-example/pkg/sharding"
+)
+
+// MapperFunc returns a [handler.MapFunc], which maps related objects to
+// [extensionsv1alpha1.Extension] resources using the given [client.Reader].
+type MapperFunc func(reader client.Reader) handler.MapFunc
+
+// watch is an additional watch of the [Controller] on objects related to the
+// [extensionsv1alpha1.Extension] resources.
+type watch struct {
+	// obj is the kind of objects to watch.
+	obj client.Object
+
+	// mapper maps the watched objects to extension resources.
+	mapper MapperFunc
+
+	// predicates filter the events for the watched objects.
+	predicates []predicate.Predicate
+}
+
+// WithWatch is an [Option], which configures the [Controller] to watch objects
+// of the given kind and to reconcile the [extensionsv1alpha1.Extension]
+// resources returned by the given [MapperFunc]. Only events, which pass all
+// given predicates are considered.
+func WithWatch(obj client.Object, mapper MapperFunc, preds ...predicate.Predicate) Option {
+	opt := func(c *Controller) error {
+		w := watch{
+			obj:        obj,
+			mapper:     mapper,
+			predicates: preds,
+		}
+		c.watches = append(c.watches, w)
+
+		return nil
+	}
+
+	return opt
+}
+
+// addToController registers the watch with the given [crctrl.Controller].
+func (w watch) addToController(mgr manager.Manager, ctrl crctrl.Controller) error {
+	src := source.Kind[client.Object](
+		mgr.GetCache(),
+		w.obj,
+		handler.EnqueueRequestsFromMapFunc(w.mapper(mgr.GetClient())),
+		w.predicates...,
+	)
+
+	return ctrl.Watch(src)
+}
+
+// extensionsInNamespace returns reconcile requests for the
+// [extensionsv1alpha1.Extension] resources of the given type in the given
+// namespace.
+func extensionsInNamespace(ctx context.Context, reader client.Reader, namespace, extensionType string) []reconcile.Request {
+	logger := ctrllog.FromContext(ctx)
+
+	var items extensionsv1alpha1.ExtensionList
+	if err := reader.List(ctx, &items, client.InNamespace(namespace)); err != nil {
+		logger.Error(err, "failed to list extensions", "namespace", namespace)
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0)
+	for _, item := range items.Items {
+		if item.Spec.Type != extensionType {
+			continue
+		}
+		req := reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&item),
+		}
+		requests = append(requests, req)
+	}
+
+	return requests
+}
+
+// ExtensionsInNamespaceMapper returns a [MapperFunc], which maps objects to
+// the [extensionsv1alpha1.Extension] resources of the given type in the same
+// namespace, e.g. ManagedResources or secrets in the shoot namespace.
+func ExtensionsInNamespaceMapper(extensionType string) MapperFunc {
+	mapper := func(reader client.Reader) handler.MapFunc {
+		return func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return extensionsInNamespace(ctx, reader, obj.GetNamespace(), extensionType)
+		}
+	}
+
+	return mapper
+}
+
+// ClusterToExtensionsMapper returns a [MapperFunc], which maps
+// [extensionsv1alpha1.Cluster] resources to the
+// [extensionsv1alpha1.Extension] resources of the given type in the shoot
+// namespace. The name of the cluster is the same as the name of the shoot
+// namespace.
+func ClusterToExtensionsMapper(extensionType string) MapperFunc {
+	mapper := func(reader client.Reader) handler.MapFunc {
+		return func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return extensionsInNamespace(ctx, reader, obj.GetName(), extensionType)
+		}
+	}
+
+	return mapper
+}
+
+// ShardedMapper returns a [MapperFunc], which drops the requests returned by
+// the given [MapperFunc] for extension resources in namespaces, which are not
+// owned by the shard of the given [sharding.Sharder].
+func ShardedMapper(sharder *sharding.Sharder, mapper MapperFunc) MapperFunc {
+	sharded := func(reader client.Reader) handler.MapFunc {
+		mapFunc := mapper(reader)
+
+		return func(ctx context.Context, obj client.Object) []reconcile.Request {
+			requests := mapFunc(ctx, obj)
+
+			return slices.DeleteFunc(requests, func(req reconcile.Request) bool {
+				return !sharder.Owns(req.Namespace)
+			})
+		}
+	}
+
+	return sharded
+}
+
+// ReplayAsCreate returns a [predicate.Predicate], which matches generic events
+// for objects, which would pass all of the given predicates when being
+// created. Generic events are used to replay objects, e.g. after rebalancing
+// the shards, and create events are what the predicates of a freshly started
+// controller evaluate for existing objects.
+func ReplayAsCreate(preds ...predicate.Predicate) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(event.UpdateEvent) bool { return false },
+		DeleteFunc: func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool {
+			if e.Object == nil {
+				return false
+			}
+
+			return predicate.And(preds...).Create(event.CreateEvent{Object: e.Object})
+		},
+	}
+}
+
+// HasNamePrefix returns a [predicate.Predicate], which matches objects with
+// the given name prefix.
+func HasNamePrefix(prefix string) predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return strings.HasPrefix(obj.GetName(), prefix)
+	})
+}
+
+// AnnotationAdded returns a [predicate.Predicate], which matches updates of
+// objects, which set the given annotation to a new non-empty value. Removing
+// the annotation does not match.
+func AnnotationAdded(annotation string) predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+
+			value := e.ObjectNew.GetAnnotations()[annotation]
+
+			return value != "" && value != e.ObjectOld.GetAnnotations()[annotation]
+		},
+	}
+}
+
+// IgnoreStatusUpdates returns a [predicate.Predicate], which ignores update
+// events that change the status of an object only. Changes to the spec of an
+// object are detected via its generation. Objects without a generation, e.g.
+// secrets, do not have a status, so all updates of such objects are
+// considered.
+func IgnoreStatusUpdates() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+
+			if e.ObjectNew.GetGeneration() == 0 {
+				return true
+			}
+
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() ||
+				!maps.Equal(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+				!maps.Equal(e.ObjectOld.GetAnnotations(), e.ObjectNew.GetAnnotations())
+		},
+	}
+}
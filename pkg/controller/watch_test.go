@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller_test
+
+import (
+	"context"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"gardener-extension-example/pkg/controller"
+	"gardener-extension-example/pkg/sharding"
+)
+
+var _ = Describe("Watches", func() {
+	var (
+		fakeClient client.Client
+		namespace  = "shoot--local--local"
+	)
+
+	BeforeEach(func() {
+		s := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(s)).To(Succeed())
+
+		newExtension := func(name, namespace, extensionType string) *extensionsv1alpha1.Extension {
+			return &extensionsv1alpha1.Extension{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: namespace,
+				},
+				Spec: extensionsv1alpha1.ExtensionSpec{
+					DefaultSpec: extensionsv1alpha1.DefaultSpec{
+						Type: extensionType,
+					},
+				},
+			}
+		}
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(s).
+			WithObjects(
+				newExtension("example", namespace, "example"),
+				newExtension("other", namespace, "other"),
+				newExtension("example", "shoot--local--other", "example"),
+			).
+			Build()
+	})
+
+	It("should map objects to extensions in the same namespace", func() {
+		mapper := controller.ExtensionsInNamespaceMapper("example")(fakeClient)
+		mr := &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "extension-example-workload",
+				Namespace: namespace,
+			},
+		}
+
+		Expect(mapper(context.Background(), mr)).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "example", Namespace: namespace},
+		}))
+	})
+
+	It("should map clusters to extensions in the shoot namespace", func() {
+		mapper := controller.ClusterToExtensionsMapper("example")(fakeClient)
+		cluster := &extensionsv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+
+		Expect(mapper(context.Background(), cluster)).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "example", Namespace: namespace},
+		}))
+	})
+
+	It("should map objects to extensions in namespaces owned by the shard", func() {
+		s := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(s)).To(Succeed())
+		Expect(coordinationv1.AddToScheme(s)).To(Succeed())
+		sharder, err := sharding.New(
+			fake.NewClientBuilder().WithScheme(s).Build(),
+			sharding.WithGroup("gardener-extension-example"),
+			sharding.WithLeaseNamespace("extension-example"),
+			sharding.WithIdentity("replica-a"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		mapper := controller.ShardedMapper(sharder, controller.ExtensionsInNamespaceMapper("example"))(fakeClient)
+		mr := &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "extension-example-workload",
+				Namespace: namespace,
+			},
+		}
+
+		// Nothing is owned before the first sync
+		Expect(mapper(context.Background(), mr)).To(BeEmpty())
+
+		// A single replica owns all namespaces
+		Expect(sharder.Sync(context.Background())).To(Succeed())
+		Expect(mapper(context.Background(), mr)).To(ConsistOf(reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: "example", Namespace: namespace},
+		}))
+	})
+
+	It("should replay generic events as create events", func() {
+		ex := &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: namespace,
+			},
+		}
+		pred := controller.ReplayAsCreate(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return e.Object.GetName() == "example" },
+			GenericFunc: func(event.GenericEvent) bool { return false },
+		})
+
+		Expect(pred.Generic(event.GenericEvent{Object: ex})).To(BeTrue())
+		Expect(pred.Generic(event.GenericEvent{Object: &extensionsv1alpha1.Extension{}})).To(BeFalse())
+		Expect(pred.Create(event.CreateEvent{Object: ex})).To(BeFalse())
+	})
+
+	It("should match objects by name prefix", func() {
+		pred := controller.HasNamePrefix("ref-")
+		Expect(pred.Generic(event.GenericEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ref-foo"}}})).To(BeTrue())
+		Expect(pred.Generic(event.GenericEvent{Object: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}})).To(BeFalse())
+	})
+
+	It("should match updates, which add an annotation", func() {
+		pred := controller.AnnotationAdded("example.extensions.gardener.cloud/operation")
+		oldObj := &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: namespace,
+			},
+		}
+
+		// Annotation added
+		newObj := oldObj.DeepCopy()
+		newObj.Annotations = map[string]string{"example.extensions.gardener.cloud/operation": "collect-diagnostics"}
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})).To(BeTrue())
+
+		// Annotation unchanged
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: newObj, ObjectNew: newObj.DeepCopy()})).To(BeFalse())
+
+		// Annotation changed
+		changedObj := newObj.DeepCopy()
+		changedObj.Annotations["example.extensions.gardener.cloud/operation"] = "recreate-components"
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: newObj, ObjectNew: changedObj})).To(BeTrue())
+
+		// Annotation removed
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: newObj, ObjectNew: oldObj})).To(BeFalse())
+
+		// Other events are not matched
+		Expect(pred.Create(event.CreateEvent{Object: newObj})).To(BeFalse())
+		Expect(pred.Delete(event.DeleteEvent{Object: newObj})).To(BeFalse())
+	})
+
+	It("should ignore status-only updates", func() {
+		pred := controller.IgnoreStatusUpdates()
+		oldObj := &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "extension-example-workload",
+				Namespace:  namespace,
+				Generation: 1,
+			},
+		}
+
+		// Status update
+		newObj := oldObj.DeepCopy()
+		newObj.Status.ObservedGeneration = 1
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})).To(BeFalse())
+
+		// Spec update
+		newObj = oldObj.DeepCopy()
+		newObj.Generation = 2
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})).To(BeTrue())
+
+		// Labels update
+		newObj = oldObj.DeepCopy()
+		newObj.Labels = map[string]string{"foo": "bar"}
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: newObj})).To(BeTrue())
+
+		// Objects without generation
+		oldSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ref-foo", Namespace: namespace}}
+		newSecret := oldSecret.DeepCopy()
+		newSecret.Data = map[string][]byte{"foo": []byte("bar")}
+		Expect(pred.Update(event.UpdateEvent{ObjectOld: oldSecret, ObjectNew: newSecret})).To(BeTrue())
+
+		// Other events are not filtered
+		Expect(pred.Create(event.CreateEvent{Object: oldObj})).To(BeTrue())
+		Expect(pred.Delete(event.DeleteEvent{Object: oldObj})).To(BeTrue())
+	})
+})
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package debug provides read-only HTTP handlers, which expose what the
+// controller knows about the extension resources it manages.
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"gardener-extension-example/pkg/controller"
+)
+
+// ExtensionsPath is the path, at which the [Handler] is usually registered.
+const ExtensionsPath = "/debug/extensions"
+
+// ErrInvalidHandler is an error, which is returned when attempting to create a
+// [Handler], but the configuration was found to be invalid.
+var ErrInvalidHandler = errors.New("invalid debug handler config")
+
+// ConfigFunc returns the effective configuration of the given
+// [extensionsv1alpha1.Extension] resource, i.e. its decoded and defaulted
+// provider config.
+type ConfigFunc func(ex *extensionsv1alpha1.Extension) (any, error)
+
+// Extensions is the response of the [Handler].
+type Extensions struct {
+	// ExtensionType is the type of the listed extension resources.
+	ExtensionType string `json:"extensionType"`
+
+	// QueueDepth is the current number of requests in the work queue of
+	// the controller.
+	QueueDepth int `json:"queueDepth"`
+
+	// Items are the extension resources seen by the controller.
+	Items []Extension `json:"items"`
+}
+
+// Extension describes an extension resource as seen by the controller.
+type Extension struct {
+	// Namespace is the namespace of the extension resource.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the extension resource.
+	Name string `json:"name"`
+
+	// LastOperation is the last call of the actuator for the extension
+	// resource by this replica, if any.
+	LastOperation *controller.OperationRecord `json:"lastOperation,omitempty"`
+
+	// Config is the effective configuration of the extension resource.
+	Config any `json:"config,omitempty"`
+
+	// ConfigError is the error, which occurred while decoding the
+	// configuration of the extension resource.
+	ConfigError string `json:"configError,omitempty"`
+}
+
+// Handler is a read-only [http.Handler], which lists the
+// [extensionsv1alpha1.Extension] resources seen by a controller as JSON.
+//
+// The handler is meant to be registered with the metrics server of the
+// manager, so that it is guarded by the same authentication and authorization
+// as the metrics endpoint.
+type Handler struct {
+	extensionType  string
+	controllerName string
+	reader         client.Reader
+	recorder       *controller.OperationRecorder
+	configFunc     ConfigFunc
+	gatherer       prometheus.Gatherer
+}
+
+var _ http.Handler = &Handler{}
+
+// Option is a function, which configures the [Handler].
+type Option func(h *Handler) error
+
+// New creates a new [Handler] with the given options.
+func New(opts ...Option) (*Handler, error) {
+	h := &Handler{
+		gatherer: ctrlmetrics.Registry,
+	}
+
+	for _, opt := range opts {
+		if err := opt(h); err != nil {
+			return nil, err
+		}
+	}
+
+	if h.extensionType == "" {
+		return nil, fmt.Errorf("%w: missing extension type", ErrInvalidHandler)
+	}
+	if h.controllerName == "" {
+		return nil, fmt.Errorf("%w: missing controller name", ErrInvalidHandler)
+	}
+
+	return h, nil
+}
+
+// SetupWithManager sets up the [Handler] with the given [manager.Manager].
+// Unless configured otherwise, the extension resources are read from the cache
+// of the manager.
+func (h *Handler) SetupWithManager(mgr manager.Manager) error {
+	if h.reader == nil {
+		h.reader = mgr.GetClient()
+	}
+
+	return nil
+}
+
+// ServeHTTP implements the [http.Handler] interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	if h.reader == nil {
+		http.Error(w, "debug handler has not been set up", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	resp, err := h.extensions(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(resp)
+}
+
+// extensions returns the extension resources of the configured type, which
+// are seen by the controller.
+func (h *Handler) extensions(req *http.Request) (*Extensions, error) {
+	var list extensionsv1alpha1.ExtensionList
+	if err := h.reader.List(req.Context(), &list); err != nil {
+		return nil, fmt.Errorf("failed to list extensions: %w", err)
+	}
+
+	depth, err := h.queueDepth()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &Extensions{
+		ExtensionType: h.extensionType,
+		QueueDepth:    depth,
+		Items:         make([]Extension, 0),
+	}
+
+	for _, ex := range list.Items {
+		if ex.Spec.Type != h.extensionType {
+			continue
+		}
+
+		item := Extension{
+			Namespace: ex.Namespace,
+			Name:      ex.Name,
+		}
+
+		if h.recorder != nil {
+			if record, ok := h.recorder.Get(client.ObjectKeyFromObject(&ex)); ok {
+				item.LastOperation = &record
+			}
+		}
+
+		if h.configFunc != nil {
+			cfg, err := h.configFunc(&ex)
+			if err != nil {
+				item.ConfigError = err.Error()
+			} else {
+				item.Config = cfg
+			}
+		}
+
+		resp.Items = append(resp.Items, item)
+	}
+
+	slices.SortFunc(resp.Items, func(a, b Extension) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	return resp, nil
+}
+
+// queueDepth returns the current depth of the work queue of the controller,
+// which is summed up across all priorities.
+func (h *Handler) queueDepth() (int, error) {
+	families, err := h.gatherer.Gather()
+	if err != nil {
+		return 0, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	var depth float64
+	for _, family := range families {
+		if family.GetName() != "workqueue_depth" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "controller" && label.GetValue() == h.controllerName {
+					depth += metric.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+
+	return int(depth), nil
+}
+
+// WithExtensionType is an [Option], which configures the [Handler] to list the
+// extension resources of the given type.
+func WithExtensionType(extensionType string) Option {
+	opt := func(h *Handler) error {
+		h.extensionType = extensionType
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithControllerName is an [Option], which configures the [Handler] to report
+// the queue depth of the controller with the given name.
+func WithControllerName(name string) Option {
+	opt := func(h *Handler) error {
+		h.controllerName = name
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithReader is an [Option], which configures the [Handler] to read the
+// extension resources with the given [client.Reader].
+func WithReader(reader client.Reader) Option {
+	opt := func(h *Handler) error {
+		h.reader = reader
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithOperationRecorder is an [Option], which configures the [Handler] to
+// report the last operations recorded by the given
+// [controller.OperationRecorder].
+func WithOperationRecorder(recorder *controller.OperationRecorder) Option {
+	opt := func(h *Handler) error {
+		h.recorder = recorder
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithConfigFunc is an [Option], which configures the [Handler] to report the
+// effective configuration of the extension resources returned by the given
+// [ConfigFunc].
+func WithConfigFunc(f ConfigFunc) Option {
+	opt := func(h *Handler) error {
+		h.configFunc = f
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithGatherer is an [Option], which configures the [Handler] to read the
+// queue depth from the given [prometheus.Gatherer]. By default the
+// controller-runtime metrics registry is used.
+func WithGatherer(gatherer prometheus.Gatherer) Option {
+	opt := func(h *Handler) error {
+		h.gatherer = gatherer
+
+		return nil
+	}
+
+	return opt
+}
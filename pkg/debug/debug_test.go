@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package debug_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"gardener-extension-example/pkg/controller"
+	"gardener-extension-example/pkg/debug"
+)
+
+// noopActuator is an actuator, which does nothing.
+type noopActuator struct{}
+
+func (noopActuator) Reconcile(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	return nil
+}
+
+func (noopActuator) Delete(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	return nil
+}
+
+func (noopActuator) ForceDelete(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	return nil
+}
+
+func (noopActuator) Restore(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	return nil
+}
+
+func (noopActuator) Migrate(context.Context, logr.Logger, *extensionsv1alpha1.Extension) error {
+	return nil
+}
+
+var _ = Describe("Debug Handler", func() {
+	var (
+		ctx      = context.Background()
+		recorder *controller.OperationRecorder
+		registry *prometheus.Registry
+		handler  *debug.Handler
+	)
+
+	// newExtension returns a new extension resource of the given type.
+	newExtension := func(namespace, extensionType, config string) *extensionsv1alpha1.Extension {
+		ex := &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "example",
+				Namespace: namespace,
+			},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type:           extensionType,
+					ProviderConfig: &runtime.RawExtension{Raw: []byte(config)},
+				},
+			},
+		}
+
+		return ex
+	}
+
+	// serve returns the response of the handler to a request with the given
+	// method.
+	serve := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, debug.ExtensionsPath, nil))
+
+		return rec
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(extensionsv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		reconciled := newExtension("shoot--local--foo", "example", `{"foo":"bar"}`)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				reconciled,
+				newExtension("shoot--local--bar", "example", `["invalid"]`),
+				newExtension("shoot--local--baz", "other", `{}`),
+			).
+			Build()
+
+		recorder = controller.NewOperationRecorder()
+		act := controller.NewRecordingActuator(noopActuator{}, recorder)
+		Expect(act.Reconcile(ctx, logr.Discard(), reconciled)).To(Succeed())
+
+		registry = prometheus.NewRegistry()
+		depth := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{Name: "workqueue_depth"},
+			[]string{"name", "controller", "priority"},
+		)
+		registry.MustRegister(depth)
+		depth.WithLabelValues("example", "example", "0").Set(2)
+		depth.WithLabelValues("example", "example", "-100").Set(1)
+		depth.WithLabelValues("heartbeat", "heartbeat", "0").Set(5)
+
+		var err error
+		handler, err = debug.New(
+			debug.WithExtensionType("example"),
+			debug.WithControllerName("example"),
+			debug.WithReader(fakeClient),
+			debug.WithOperationRecorder(recorder),
+			debug.WithGatherer(registry),
+			debug.WithConfigFunc(func(ex *extensionsv1alpha1.Extension) (any, error) {
+				var cfg map[string]string
+				if err := json.Unmarshal(ex.Spec.ProviderConfig.Raw, &cfg); err != nil {
+					return nil, errors.New("invalid provider config")
+				}
+
+				return cfg, nil
+			}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fail to create a handler without an extension type", func() {
+		h, err := debug.New(debug.WithControllerName("example"))
+
+		Expect(err).To(MatchError(debug.ErrInvalidHandler))
+		Expect(err).To(MatchError(ContainSubstring("missing extension type")))
+		Expect(h).To(BeNil())
+	})
+
+	It("should fail to create a handler without a controller name", func() {
+		h, err := debug.New(debug.WithExtensionType("example"))
+
+		Expect(err).To(MatchError(debug.ErrInvalidHandler))
+		Expect(err).To(MatchError(ContainSubstring("missing controller name")))
+		Expect(h).To(BeNil())
+	})
+
+	It("should list the extensions with their state", func() {
+		rec := serve(http.MethodGet)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var resp debug.Extensions
+		Expect(json.Unmarshal(rec.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.ExtensionType).To(Equal("example"))
+		Expect(resp.QueueDepth).To(Equal(3))
+		Expect(resp.Items).To(HaveLen(2))
+
+		Expect(resp.Items[0].Namespace).To(Equal("shoot--local--bar"))
+		Expect(resp.Items[0].LastOperation).To(BeNil())
+		Expect(resp.Items[0].Config).To(BeNil())
+		Expect(resp.Items[0].ConfigError).To(Equal("invalid provider config"))
+
+		Expect(resp.Items[1].Namespace).To(Equal("shoot--local--foo"))
+		Expect(resp.Items[1].LastOperation).NotTo(BeNil())
+		Expect(resp.Items[1].LastOperation.Operation).To(Equal("Reconcile"))
+		Expect(resp.Items[1].LastOperation.Result).To(Equal(controller.OperationResultSucceeded))
+		Expect(resp.Items[1].Config).To(Equal(map[string]any{"foo": "bar"}))
+		Expect(resp.Items[1].ConfigError).To(BeEmpty())
+	})
+
+	It("should reject requests, which are not read-only", func() {
+		rec := serve(http.MethodPost)
+		Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(rec.Header().Get("Allow")).To(Equal(http.MethodGet))
+	})
+
+	It("should be unavailable until it has been set up", func() {
+		var err error
+		handler, err = debug.New(
+			debug.WithExtensionType("example"),
+			debug.WithControllerName("example"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(serve(http.MethodGet).Code).To(Equal(http.StatusServiceUnavailable))
+	})
+})
@@ -0,0 +1,401 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package gc provides a garbage collector, which periodically removes objects
+// created by the extension for shoot namespaces, whose Extension or Cluster
+// resources no longer exist.
+package gc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"gardener-extension-example/pkg/metrics"
+)
+
+const (
+	// DefaultInterval is the default interval on which the [Collector]
+	// looks for orphaned objects.
+	DefaultInterval = 10 * time.Minute
+
+	// DefaultGracePeriod is the default duration for which an object must
+	// be orphaned, before it is deleted by the [Collector].
+	DefaultGracePeriod = time.Hour
+)
+
+// ErrInvalidCollector is an error, which is returned when creating a
+// [Collector] with invalid config settings.
+var ErrInvalidCollector = errors.New("invalid garbage collector")
+
+// Collector is a [manager.Runnable], which periodically lists the objects
+// labeled as owned by the extension, detects those in namespaces, whose
+// Extension or Cluster resource no longer exists, and deletes them after a
+// grace period.
+//
+// [manager.Runnable]: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Collector struct {
+	client        client.Client
+	extensionType string
+	selectors     []labels.Selector
+	lists         []client.ObjectList
+	interval      time.Duration
+	gracePeriod   time.Duration
+	dryRun        bool
+	clock         clock.Clock
+	logger        logr.Logger
+
+	// orphanedSince tracks the time, when the orphaned objects were
+	// detected first, keyed by their kind and namespaced name.
+	orphanedSince map[string]time.Time
+	mu            sync.Mutex
+}
+
+// Option is a function, which configures the [Collector].
+type Option func(c *Collector) error
+
+// New creates a new [Collector], which uses the given [client.Client] to
+// collect the objects configured via the given options.
+func New(c client.Client, opts ...Option) (*Collector, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: no client specified", ErrInvalidCollector)
+	}
+
+	collector := &Collector{
+		client:        c,
+		selectors:     make([]labels.Selector, 0),
+		lists:         make([]client.ObjectList, 0),
+		interval:      DefaultInterval,
+		gracePeriod:   DefaultGracePeriod,
+		clock:         clock.RealClock{},
+		logger:        ctrllog.Log.WithName("garbage-collector"),
+		orphanedSince: make(map[string]time.Time),
+	}
+
+	for _, opt := range opts {
+		if err := opt(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	if collector.extensionType == "" {
+		return nil, fmt.Errorf("%w: missing extension type", ErrInvalidCollector)
+	}
+	if len(collector.lists) == 0 {
+		return nil, fmt.Errorf("%w: no resources to collect", ErrInvalidCollector)
+	}
+	if collector.interval <= 0 {
+		return nil, fmt.Errorf("%w: interval must be positive", ErrInvalidCollector)
+	}
+	if collector.gracePeriod < 0 {
+		return nil, fmt.Errorf("%w: grace period must not be negative", ErrInvalidCollector)
+	}
+
+	return collector, nil
+}
+
+// WithExtensionType is an [Option], which configures the [Collector] to treat
+// objects as orphaned, when there is no Extension resource of the given type
+// in their namespace.
+func WithExtensionType(extensionType string) Option {
+	opt := func(c *Collector) error {
+		c.extensionType = extensionType
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithSelector is an [Option], which configures the [Collector] to consider
+// only objects matching the given label selector, i.e. objects labeled as
+// owned by the extension. The option may be specified multiple times, e.g.
+// for the secrets generated by a secrets manager, in which case objects
+// matching any of the selectors are considered. Without any selector, all
+// objects are considered.
+func WithSelector(selector labels.Selector) Option {
+	opt := func(c *Collector) error {
+		c.selectors = append(c.selectors, selector)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithResources is an [Option], which configures the [Collector] to collect
+// the objects of the given list types, e.g. a
+// [resourcesv1alpha1.ManagedResourceList].
+//
+// [resourcesv1alpha1.ManagedResourceList]: https://pkg.go.dev/github.com/gardener/gardener/pkg/apis/resources/v1alpha1#ManagedResourceList
+func WithResources(lists ...client.ObjectList) Option {
+	opt := func(c *Collector) error {
+		c.lists = append(c.lists, lists...)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithInterval is an [Option], which configures the [Collector] to look for
+// orphaned objects on the given interval.
+func WithInterval(interval time.Duration) Option {
+	opt := func(c *Collector) error {
+		c.interval = interval
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithGracePeriod is an [Option], which configures the [Collector] to delete
+// orphaned objects only after they have been orphaned for the given duration.
+func WithGracePeriod(gracePeriod time.Duration) Option {
+	opt := func(c *Collector) error {
+		c.gracePeriod = gracePeriod
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithDryRun is an [Option], which configures the [Collector] to only report
+// orphaned objects in the logs and metrics, instead of deleting them.
+func WithDryRun(dryRun bool) Option {
+	opt := func(c *Collector) error {
+		c.dryRun = dryRun
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithClock is an [Option], which configures the [Collector] to use the given
+// [clock.Clock].
+func WithClock(clk clock.Clock) Option {
+	opt := func(c *Collector) error {
+		c.clock = clk
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithLogger is an [Option], which configures the [Collector] to use the given
+// [logr.Logger].
+func WithLogger(logger logr.Logger) Option {
+	opt := func(c *Collector) error {
+		c.logger = logger
+
+		return nil
+	}
+
+	return opt
+}
+
+// Start starts the [Collector] and blocks until the given context is
+// cancelled. This method implements the [manager.Runnable] interface.
+//
+// [manager.Runnable]: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+func (c *Collector) Start(ctx context.Context) error {
+	c.logger.Info("starting garbage collector", "interval", c.interval, "gracePeriod", c.gracePeriod, "dryRun", c.dryRun)
+	wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.Collect(ctx); err != nil {
+			c.logger.Error(err, "failed to collect orphaned objects")
+		}
+	}, c.interval, 0.1, true)
+
+	return nil
+}
+
+// NeedLeaderElection returns true, so that only the leader collects orphaned
+// objects. This method implements the [manager.LeaderElectionRunnable]
+// interface.
+//
+// [manager.LeaderElectionRunnable]: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#LeaderElectionRunnable
+func (c *Collector) NeedLeaderElection() bool {
+	return true
+}
+
+// Collect performs a single run of the [Collector]. Orphaned objects are
+// deleted, once they have been orphaned for longer than the grace period.
+func (c *Collector) Collect(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := &collectRun{
+		now:                c.clock.Now(),
+		orphanedNamespaces: make(map[string]bool),
+		seen:               make(map[string]struct{}),
+		errs:               make([]error, 0),
+	}
+
+	selectors := c.selectors
+	if len(selectors) == 0 {
+		selectors = []labels.Selector{labels.Everything()}
+	}
+
+	for _, list := range c.lists {
+		kind, err := c.kindOf(list)
+		if err != nil {
+			return err
+		}
+
+		found := 0
+		for _, selector := range selectors {
+			if err := c.client.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+				return fmt.Errorf("failed to list %s: %w", kind, err)
+			}
+
+			n, err := c.collectItems(ctx, r, kind, list)
+			if err != nil {
+				return err
+			}
+			found += n
+		}
+
+		metrics.GarbageCollectorOrphansFound.WithLabelValues(kind).Set(float64(found))
+	}
+
+	// Forget about objects, which are no longer orphaned or have been
+	// deleted by someone else.
+	for key := range c.orphanedSince {
+		if _, ok := r.seen[key]; !ok {
+			delete(c.orphanedSince, key)
+		}
+	}
+
+	return errors.Join(r.errs...)
+}
+
+// collectRun is the state of a single run of the [Collector].
+type collectRun struct {
+	now                time.Time
+	orphanedNamespaces map[string]bool
+	seen               map[string]struct{}
+	errs               []error
+}
+
+// collectItems collects the orphaned objects of the given kind from the given
+// list and returns the number of orphaned objects found. Objects, which have
+// been seen already during the run, e.g. because they match multiple
+// selectors, are skipped.
+func (c *Collector) collectItems(ctx context.Context, r *collectRun, kind string, list client.ObjectList) (int, error) {
+	found := 0
+	err := meta.EachListItem(list, func(o runtime.Object) error {
+		obj, ok := o.(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected object type %T", o)
+		}
+
+		namespace := obj.GetNamespace()
+		orphaned, ok := r.orphanedNamespaces[namespace]
+		if !ok {
+			var err error
+			if orphaned, err = c.isOrphanedNamespace(ctx, namespace); err != nil {
+				return err
+			}
+			r.orphanedNamespaces[namespace] = orphaned
+		}
+
+		if !orphaned || obj.GetDeletionTimestamp() != nil {
+			return nil
+		}
+
+		key := kind + "/" + client.ObjectKeyFromObject(obj).String()
+		if _, ok := r.seen[key]; ok {
+			return nil
+		}
+		r.seen[key] = struct{}{}
+		found++
+		since, ok := c.orphanedSince[key]
+		if !ok {
+			since = r.now
+			c.orphanedSince[key] = since
+		}
+
+		logger := c.logger.WithValues("kind", kind, "object", client.ObjectKeyFromObject(obj), "orphanedSince", since)
+		if r.now.Sub(since) < c.gracePeriod {
+			logger.Info("found orphaned object within grace period")
+
+			return nil
+		}
+
+		if c.dryRun {
+			logger.Info("would delete orphaned object (dry-run)")
+
+			return nil
+		}
+
+		logger.Info("deleting orphaned object")
+		if err := c.client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			r.errs = append(r.errs, fmt.Errorf("failed to delete %s %s: %w", kind, client.ObjectKeyFromObject(obj), err))
+
+			return nil
+		}
+
+		delete(c.orphanedSince, key)
+		metrics.GarbageCollectorOrphansRemovedTotal.WithLabelValues(kind).Inc()
+
+		return nil
+	})
+
+	return found, err
+}
+
+// isOrphanedNamespace returns true, if there is no Cluster resource for the
+// given shoot namespace, or no Extension resource of the configured type in it.
+func (c *Collector) isOrphanedNamespace(ctx context.Context, namespace string) (bool, error) {
+	cluster := &extensionsv1alpha1.Cluster{}
+	if err := c.client.Get(ctx, client.ObjectKey{Name: namespace}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("failed to get cluster %s: %w", namespace, err)
+	}
+
+	var extensions extensionsv1alpha1.ExtensionList
+	if err := c.client.List(ctx, &extensions, client.InNamespace(namespace)); err != nil {
+		return false, fmt.Errorf("failed to list extensions in namespace %s: %w", namespace, err)
+	}
+
+	for _, ex := range extensions.Items {
+		if ex.Spec.Type == c.extensionType {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// kindOf returns the kind of the objects in the given list.
+func (c *Collector) kindOf(list client.ObjectList) (string, error) {
+	gvk, err := apiutil.GVKForObject(list, c.client.Scheme())
+	if err != nil {
+		return "", fmt.Errorf("failed to get kind of list %T: %w", list, err)
+	}
+
+	return strings.TrimSuffix(gvk.Kind, "List"), nil
+}
@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gc_test
+
+import (
+	"context"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	testclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"gardener-extension-example/pkg/gc"
+)
+
+var _ = Describe("Collector", func() {
+	const (
+		extensionType = "example"
+		gracePeriod   = time.Hour
+	)
+
+	var (
+		ctx       = context.Background()
+		fakeClock *testclock.FakeClock
+		ownedBy   = map[string]string{"app.kubernetes.io/managed-by": "gardener-extension-example"}
+		generated = map[string]string{"managed-by": "secrets-manager", "manager-identity": "extension-example"}
+	)
+
+	// newManagedResource returns a ManagedResource owned by the extension
+	// in the given namespace.
+	newManagedResource := func(namespace string) *resourcesv1alpha1.ManagedResource {
+		return &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "extension-example-workload",
+				Namespace: namespace,
+				Labels:    ownedBy,
+			},
+		}
+	}
+
+	// newCollector returns a new collector using a fake client with the
+	// given objects.
+	newCollector := func(dryRun bool, objs ...client.Object) (*gc.Collector, client.Client) {
+		c := fake.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithObjects(objs...).Build()
+		collector, err := gc.New(
+			c,
+			gc.WithExtensionType(extensionType),
+			gc.WithSelector(labels.SelectorFromSet(ownedBy)),
+			gc.WithSelector(labels.SelectorFromSet(generated)),
+			gc.WithResources(&resourcesv1alpha1.ManagedResourceList{}, &corev1.SecretList{}),
+			gc.WithGracePeriod(gracePeriod),
+			gc.WithDryRun(dryRun),
+			gc.WithClock(fakeClock),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		return collector, c
+	}
+
+	// exists returns true, if the given object exists.
+	exists := func(c client.Client, obj client.Object) bool {
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			return false
+		}
+		Expect(err).NotTo(HaveOccurred())
+
+		return true
+	}
+
+	BeforeEach(func() {
+		fakeClock = testclock.NewFakeClock(time.Now())
+	})
+
+	It("should fail to create collector with invalid settings", func() {
+		c := fake.NewClientBuilder().Build()
+
+		_, err := gc.New(nil)
+		Expect(err).To(MatchError(gc.ErrInvalidCollector))
+
+		_, err = gc.New(c, gc.WithResources(&corev1.SecretList{}))
+		Expect(err).To(MatchError(gc.ErrInvalidCollector))
+
+		_, err = gc.New(c, gc.WithExtensionType(extensionType))
+		Expect(err).To(MatchError(gc.ErrInvalidCollector))
+
+		_, err = gc.New(c, gc.WithExtensionType(extensionType), gc.WithResources(&corev1.SecretList{}), gc.WithInterval(0))
+		Expect(err).To(MatchError(gc.ErrInvalidCollector))
+	})
+
+	It("should delete orphaned objects after the grace period", func() {
+		// The extension exists in shoot--foo--bar, but not in
+		// shoot--foo--baz, and there is no cluster for shoot--foo--qux.
+		cluster := &extensionsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--bar"}}
+		orphanedCluster := &extensionsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--baz"}}
+		ex := &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "shoot--foo--bar"},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: extensionType},
+			},
+		}
+		otherEx := &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "shoot--foo--baz"},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: "other"},
+			},
+		}
+
+		owned := newManagedResource("shoot--foo--bar")
+		orphaned := newManagedResource("shoot--foo--baz")
+		orphanedSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "managedresource-extension-example-workload", Namespace: "shoot--foo--qux", Labels: ownedBy},
+		}
+		unlabeled := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "shoot--foo--qux"},
+		}
+
+		collector, c := newCollector(false, cluster, orphanedCluster, ex, otherEx, owned, orphaned, orphanedSecret, unlabeled)
+
+		// Orphaned objects are kept during the grace period
+		Expect(collector.Collect(ctx)).To(Succeed())
+		Expect(exists(c, orphaned)).To(BeTrue())
+		Expect(exists(c, orphanedSecret)).To(BeTrue())
+
+		fakeClock.Step(gracePeriod)
+		Expect(collector.Collect(ctx)).To(Succeed())
+		Expect(exists(c, owned)).To(BeTrue())
+		Expect(exists(c, unlabeled)).To(BeTrue())
+		Expect(exists(c, orphaned)).To(BeFalse())
+		Expect(exists(c, orphanedSecret)).To(BeFalse())
+	})
+
+	It("should delete orphaned objects matching any selector", func() {
+		generatedSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "extension-example-token-1a2b3c4d", Namespace: "shoot--foo--bar", Labels: generated},
+		}
+		bothLabels := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "both", Namespace: "shoot--foo--bar", Labels: map[string]string{}},
+		}
+		for _, l := range []map[string]string{ownedBy, generated} {
+			for k, v := range l {
+				bothLabels.Labels[k] = v
+			}
+		}
+		otherIdentity := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-identity",
+				Namespace: "shoot--foo--bar",
+				Labels:    map[string]string{"managed-by": "secrets-manager", "manager-identity": "other"},
+			},
+		}
+
+		collector, c := newCollector(false, generatedSecret, bothLabels, otherIdentity)
+
+		Expect(collector.Collect(ctx)).To(Succeed())
+		fakeClock.Step(gracePeriod)
+		Expect(collector.Collect(ctx)).To(Succeed())
+		Expect(exists(c, generatedSecret)).To(BeFalse())
+		Expect(exists(c, bothLabels)).To(BeFalse())
+		Expect(exists(c, otherIdentity)).To(BeTrue())
+	})
+
+	It("should restart the grace period, when an object is no longer orphaned", func() {
+		orphaned := newManagedResource("shoot--foo--bar")
+		collector, c := newCollector(false, orphaned)
+
+		Expect(collector.Collect(ctx)).To(Succeed())
+
+		// The extension is restored, before the grace period elapsed
+		cluster := &extensionsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "shoot--foo--bar"}}
+		ex := &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{Name: "example", Namespace: "shoot--foo--bar"},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{Type: extensionType},
+			},
+		}
+		Expect(c.Create(ctx, cluster)).To(Succeed())
+		Expect(c.Create(ctx, ex)).To(Succeed())
+		fakeClock.Step(gracePeriod / 2)
+		Expect(collector.Collect(ctx)).To(Succeed())
+
+		// The extension is removed again
+		Expect(c.Delete(ctx, ex)).To(Succeed())
+		fakeClock.Step(gracePeriod / 2)
+		Expect(collector.Collect(ctx)).To(Succeed())
+		Expect(exists(c, orphaned)).To(BeTrue())
+
+		fakeClock.Step(gracePeriod)
+		Expect(collector.Collect(ctx)).To(Succeed())
+		Expect(exists(c, orphaned)).To(BeFalse())
+	})
+
+	It("should not delete orphaned objects in dry-run mode", func() {
+		orphaned := newManagedResource("shoot--foo--bar")
+		collector, c := newCollector(true, orphaned)
+
+		Expect(collector.Collect(ctx)).To(Succeed())
+		fakeClock.Step(2 * gracePeriod)
+		Expect(collector.Collect(ctx)).To(Succeed())
+		Expect(exists(c, orphaned)).To(BeTrue())
+	})
+})
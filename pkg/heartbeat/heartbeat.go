@@ -10,24 +10,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	heartbeatcontroller "github.com/gardener/gardener/extensions/pkg/controller/heartbeat"
+	"github.com/gardener/gardener/pkg/controllerutils"
+	"github.com/gardener/gardener/pkg/extensions"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"gardener-extension-example/pkg/metrics"
 )
 
-// ErrInvalidHeartbeat is an error, which is returned when attempting to create
-// a [Heartbeat], but the configuration was found to be invalid.
-var ErrInvalidHeartbeat = errors.New("invalid heartbeat config")
+// DefaultFailureThreshold is the default number of renew intervals, after
+// which a heartbeat lease, which has not been renewed, is considered unhealthy.
+const DefaultFailureThreshold = 3
+
+var (
+	// ErrInvalidHeartbeat is an error, which is returned when attempting to
+	// create a [Heartbeat], but the configuration was found to be invalid.
+	ErrInvalidHeartbeat = errors.New("invalid heartbeat config")
+
+	// ErrHeartbeatUnhealthy is an error, which is returned by the
+	// [healthz.Checker] of a [Heartbeat], when the heartbeat lease has not
+	// been renewed in time.
+	ErrHeartbeatUnhealthy = errors.New("heartbeat lease is not renewed")
+)
 
 // Heartbeat is a wrapper for a reconciler, which periodically renews heartbeat
 // leases.
 type Heartbeat struct {
-	extensionName string
-	namespace     string
-	renewInterval time.Duration
-	clock         clock.Clock
+	extensionName    string
+	namespace        string
+	leaseName        string
+	renewInterval    time.Duration
+	failureThreshold int
+	client           client.Client
+	reader           client.Reader
+	clock            clock.Clock
 }
 
 // Option is a function, which configures the [Heartbeat].
@@ -36,8 +64,10 @@ type Option func(a *Heartbeat) error
 // New creates a new [Heartbeat] with the given options.
 func New(opts ...Option) (*Heartbeat, error) {
 	h := &Heartbeat{
-		clock:         clock.RealClock{},
-		renewInterval: 30 * time.Second,
+		clock:            clock.RealClock{},
+		leaseName:        extensions.HeartBeatResourceName,
+		renewInterval:    30 * time.Second,
+		failureThreshold: DefaultFailureThreshold,
 	}
 
 	for _, opt := range opts {
@@ -52,21 +82,133 @@ func New(opts ...Option) (*Heartbeat, error) {
 	if h.namespace == "" {
 		return nil, fmt.Errorf("%w: missing lease namespace", ErrInvalidHeartbeat)
 	}
+	if h.leaseName == "" {
+		return nil, fmt.Errorf("%w: missing lease name", ErrInvalidHeartbeat)
+	}
+	if h.renewInterval < time.Second {
+		return nil, fmt.Errorf("%w: renew interval must be at least 1s", ErrInvalidHeartbeat)
+	}
+	if h.failureThreshold < 1 {
+		return nil, fmt.Errorf("%w: failure threshold must be positive", ErrInvalidHeartbeat)
+	}
 
 	return h, nil
 }
 
-// SetupWithManager registers the [Heartbeat] controller with the given [manager.Manager].
+// SetupWithManager registers the [Heartbeat] controller with the given
+// [manager.Manager]. Unless configured otherwise, the lease is maintained in
+// the cluster of the manager.
 func (h *Heartbeat) SetupWithManager(ctx context.Context, mgr manager.Manager) error {
-	return heartbeatcontroller.Add(
-		mgr,
-		heartbeatcontroller.AddArgs{
-			ExtensionName:        h.extensionName,
-			Namespace:            h.namespace,
-			RenewIntervalSeconds: int32(h.renewInterval.Seconds()),
-			Clock:                h.clock,
-		},
-	)
+	if h.client == nil {
+		h.client = mgr.GetClient()
+	}
+	if h.reader == nil {
+		h.reader = mgr.GetAPIReader()
+	}
+
+	opts := controller.Options{
+		MaxConcurrentReconciles: 1,
+		ReconciliationTimeout:   controllerutils.DefaultReconciliationTimeout,
+	}
+
+	return builder.
+		ControllerManagedBy(mgr).
+		Named(heartbeatcontroller.ControllerName).
+		WithOptions(opts).
+		WatchesRawSource(controllerutils.EnqueueOnce).
+		Complete(&reconciler{heartbeat: h})
+}
+
+// Renew renews the heartbeat lease, or creates it, if it does not exist yet.
+// The lease is read with the API reader, so that no informer is started for
+// leases, which would be limited to the namespaces cached by the manager.
+func (h *Heartbeat) Renew(ctx context.Context) error {
+	if h.client == nil || h.reader == nil {
+		return fmt.Errorf("%w: heartbeat has not been set up", ErrInvalidHeartbeat)
+	}
+
+	renewIntervalSeconds := int32(h.renewInterval.Seconds())
+	spec := coordinationv1.LeaseSpec{
+		HolderIdentity:       &h.extensionName,
+		LeaseDurationSeconds: &renewIntervalSeconds,
+		RenewTime:            &metav1.MicroTime{Time: h.clock.Now().UTC()},
+	}
+
+	lease := &coordinationv1.Lease{}
+	key := client.ObjectKey{Namespace: h.namespace, Name: h.leaseName}
+	if err := h.reader.Get(ctx, key, lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get lease %s: %w", key, err)
+		}
+
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+			Spec:       spec,
+		}
+		if err := h.client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("failed to create lease %s: %w", key, err)
+		}
+
+		return nil
+	}
+
+	patch := client.MergeFrom(lease.DeepCopy())
+	lease.Spec = spec
+	if err := h.client.Patch(ctx, lease, patch); err != nil {
+		return fmt.Errorf("failed to renew lease %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Checker returns a [healthz.Checker], which fails when the heartbeat lease
+// has not been renewed within the failure threshold times the renew interval,
+// e.g. because the controller is stuck or lost access to the cluster. The
+// lease is read with the API reader of the manager, which the [Heartbeat] has
+// been set up with, so that no informer is started for leases.
+func (h *Heartbeat) Checker() healthz.Checker {
+	return func(req *http.Request) error {
+		if h.reader == nil {
+			return fmt.Errorf("%w: heartbeat has not been set up", ErrHeartbeatUnhealthy)
+		}
+
+		lease := &coordinationv1.Lease{}
+		key := client.ObjectKey{Namespace: h.namespace, Name: h.leaseName}
+		if err := h.reader.Get(req.Context(), key, lease); err != nil {
+			return fmt.Errorf("%w: failed to get lease %s: %w", ErrHeartbeatUnhealthy, key, err)
+		}
+
+		if lease.Spec.RenewTime == nil {
+			return fmt.Errorf("%w: lease %s has never been renewed", ErrHeartbeatUnhealthy, key)
+		}
+
+		threshold := time.Duration(h.failureThreshold) * h.renewInterval
+		if since := h.clock.Since(lease.Spec.RenewTime.Time); since > threshold {
+			return fmt.Errorf("%w: lease %s has not been renewed for %s", ErrHeartbeatUnhealthy, key, since.Round(time.Second))
+		}
+
+		return nil
+	}
+}
+
+// reconciler is a [reconcile.Reconciler], which periodically renews the
+// heartbeat lease and records the renewals in the metrics.
+type reconciler struct {
+	heartbeat *Heartbeat
+}
+
+// Reconcile renews the heartbeat lease. This method implements the
+// [reconcile.Reconciler] interface.
+func (r *reconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	if err := r.heartbeat.Renew(ctx); err != nil {
+		metrics.HeartbeatRenewalErrorsTotal.Inc()
+
+		return reconcile.Result{}, err
+	}
+
+	metrics.HeartbeatLastRenewalTimestampSeconds.Set(float64(r.heartbeat.clock.Now().Unix()))
+
+	return reconcile.Result{RequeueAfter: r.heartbeat.renewInterval}, nil
 }
 
 // WithExtensionName is an [Option], which configures the [Heartbeat] to use the
@@ -93,6 +235,18 @@ func WithLeaseNamespace(namespace string) Option {
 	return opt
 }
 
+// WithLeaseName is an [Option], which configures the [Heartbeat] to use the
+// given name for the lease. By default the name expected by Gardener is used.
+func WithLeaseName(name string) Option {
+	opt := func(h *Heartbeat) error {
+		h.leaseName = name
+
+		return nil
+	}
+
+	return opt
+}
+
 // WithRenewInterval is an [Option], which configures the [Heartbeat] to renew
 // the lease on the given interval.
 func WithRenewInterval(interval time.Duration) Option {
@@ -116,3 +270,42 @@ func WithClock(clk clock.Clock) Option {
 
 	return opt
 }
+
+// WithFailureThreshold is an [Option], which configures the [Heartbeat] to
+// consider the lease unhealthy, once it has not been renewed for the given
+// number of renew intervals.
+func WithFailureThreshold(n int) Option {
+	opt := func(h *Heartbeat) error {
+		h.failureThreshold = n
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithClient is an [Option], which configures the [Heartbeat] to renew the
+// lease with the given [client.Client], e.g. the client of a cluster other
+// than the one of the manager. By default the client of the manager is used.
+func WithClient(c client.Client) Option {
+	opt := func(h *Heartbeat) error {
+		h.client = c
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithReader is an [Option], which configures the [Heartbeat] to read the lease
+// with the given [client.Reader]. By default the API reader of the manager is
+// used.
+func WithReader(r client.Reader) Option {
+	opt := func(h *Heartbeat) error {
+		h.reader = r
+
+		return nil
+	}
+
+	return opt
+}
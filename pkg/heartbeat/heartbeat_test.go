@@ -6,12 +6,19 @@ package heartbeat_test
 
 import (
 	"context"
+	"net/http/httptest"
 	"time"
 
+	"github.com/gardener/gardener/pkg/extensions"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/utils/clock"
+	testclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"gardener-extension-example/pkg/heartbeat"
@@ -57,4 +64,110 @@ var _ = Describe("Heartbeat Controller", Ordered, func() {
 		Expect(m).NotTo(BeNil())
 		Expect(h.SetupWithManager(context.TODO(), m)).To(Succeed())
 	})
+
+	It("should fail to create heartbeat controller with invalid failure threshold", func() {
+		opts := []heartbeat.Option{
+			heartbeat.WithExtensionName("example"),
+			heartbeat.WithLeaseNamespace("default"),
+			heartbeat.WithFailureThreshold(0),
+		}
+		c, err := heartbeat.New(opts...)
+
+		Expect(err).Should(HaveOccurred())
+		Expect(err).To(MatchError(heartbeat.ErrInvalidHeartbeat))
+		Expect(err).To(MatchError(ContainSubstring("failure threshold must be positive")))
+		Expect(c).To(BeNil())
+	})
+
+	It("should report the health of the heartbeat lease", func() {
+		fakeClock := testclock.NewFakeClock(time.Now().Truncate(time.Second))
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      extensions.HeartBeatResourceName,
+			},
+		}
+		reader := fake.NewClientBuilder().WithObjects(lease).Build()
+
+		h, err := heartbeat.New(
+			heartbeat.WithExtensionName("example"),
+			heartbeat.WithLeaseNamespace("default"),
+			heartbeat.WithRenewInterval(30*time.Second),
+			heartbeat.WithFailureThreshold(3),
+			heartbeat.WithReader(reader),
+			heartbeat.WithClock(fakeClock),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		check := h.Checker()
+		req := httptest.NewRequest("GET", "/readyz", nil)
+
+		// The lease has never been renewed
+		Expect(check(req)).To(MatchError(heartbeat.ErrHeartbeatUnhealthy))
+		Expect(check(req)).To(MatchError(ContainSubstring("has never been renewed")))
+
+		// The lease has been renewed recently
+		lease.Spec.RenewTime = &metav1.MicroTime{Time: fakeClock.Now()}
+		Expect(reader.Update(context.Background(), lease)).To(Succeed())
+		fakeClock.Step(90 * time.Second)
+		Expect(check(req)).To(Succeed())
+
+		// The lease has not been renewed for more than three renew intervals
+		fakeClock.Step(time.Second)
+		Expect(check(req)).To(MatchError(heartbeat.ErrHeartbeatUnhealthy))
+		Expect(check(req)).To(MatchError(ContainSubstring("has not been renewed for 1m31s")))
+
+		// The lease does not exist
+		Expect(reader.Delete(context.Background(), lease)).To(Succeed())
+		Expect(check(req)).To(MatchError(heartbeat.ErrHeartbeatUnhealthy))
+		Expect(check(req)).To(MatchError(ContainSubstring("failed to get lease")))
+	})
+
+	It("should fail the health check, if the heartbeat has not been set up", func() {
+		h, err := heartbeat.New(
+			heartbeat.WithExtensionName("example"),
+			heartbeat.WithLeaseNamespace("default"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		Expect(h.Checker()(req)).To(MatchError(ContainSubstring("heartbeat has not been set up")))
+	})
+
+	It("should create and renew the heartbeat lease with the given client", func() {
+		ctx := context.Background()
+		fakeClock := testclock.NewFakeClock(time.Now().Truncate(time.Second))
+		c := fake.NewClientBuilder().Build()
+
+		h, err := heartbeat.New(
+			heartbeat.WithExtensionName("example-admission"),
+			heartbeat.WithLeaseNamespace("default"),
+			heartbeat.WithLeaseName("example-admission-heartbeat"),
+			heartbeat.WithRenewInterval(30*time.Second),
+			heartbeat.WithClient(c),
+			heartbeat.WithReader(c),
+			heartbeat.WithClock(fakeClock),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		lease := &coordinationv1.Lease{}
+		key := client.ObjectKey{Namespace: "default", Name: "example-admission-heartbeat"}
+
+		// The lease is created on the first renewal
+		Expect(h.Renew(ctx)).To(Succeed())
+		Expect(c.Get(ctx, key, lease)).To(Succeed())
+		Expect(lease.Spec.HolderIdentity).To(HaveValue(Equal("example-admission")))
+		Expect(lease.Spec.LeaseDurationSeconds).To(HaveValue(BeEquivalentTo(30)))
+		Expect(lease.Spec.RenewTime.Time).To(BeTemporally("==", fakeClock.Now()))
+
+		// The lease is renewed subsequently
+		fakeClock.Step(2 * time.Minute)
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		Expect(h.Checker()(req)).To(MatchError(heartbeat.ErrHeartbeatUnhealthy))
+
+		Expect(h.Renew(ctx)).To(Succeed())
+		Expect(c.Get(ctx, key, lease)).To(Succeed())
+		Expect(lease.Spec.RenewTime.Time).To(BeTemporally("==", fakeClock.Now()))
+		Expect(h.Checker()(req)).To(Succeed())
+	})
 })
@@ -0,0 +1,375 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package manifest provides offline validation of the extension
+// configuration found in YAML manifests, e.g. bare ExampleConfig documents or
+// complete Shoot manifests.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gardener/gardener/pkg/apis/core"
+	gardencoreinstall "github.com/gardener/gardener/pkg/apis/core/install"
+	"go.yaml.in/yaml/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	exampleactuator "gardener-extension-example/pkg/actuator/example"
+	admissionvalidator "gardener-extension-example/pkg/admission/validator"
+	"gardener-extension-example/pkg/apis/config"
+	configinstall "gardener-extension-example/pkg/apis/config/install"
+	"gardener-extension-example/pkg/apis/config/validation"
+)
+
+// strictErrorRegexp matches the errors reported by the strict decoder for
+// unknown and duplicate fields.
+var strictErrorRegexp = regexp.MustCompile(`^(?:unknown|duplicate) field "(.*)"$`)
+
+// Problem is a problem found in a manifest.
+type Problem struct {
+	// File is the name of the file, which contains the manifest.
+	File string
+
+	// Line is the line in the file, which the problem refers to.
+	Line int
+
+	// Field is the path of the field, which the problem refers to. It is
+	// empty for problems, which do not refer to a specific field.
+	Field string
+
+	// Detail describes the problem.
+	Detail string
+}
+
+// String implements the [fmt.Stringer] interface.
+func (p Problem) String() string {
+	if p.Field == "" {
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Detail)
+	}
+
+	return fmt.Sprintf("%s:%d: %s: %s", p.File, p.Line, p.Field, p.Detail)
+}
+
+// Validator validates the extension configuration found in YAML manifests
+// with the same strict decoding and validation as the admission webhook.
+type Validator struct {
+	decoder       runtime.Decoder
+	extensionType string
+}
+
+// Option is a function, which configures the [Validator].
+type Option func(v *Validator) error
+
+// New creates a new [Validator] with the given options.
+func New(opts ...Option) (*Validator, error) {
+	v := &Validator{
+		extensionType: exampleactuator.ExtensionType,
+	}
+
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+
+	if v.decoder == nil {
+		scheme := runtime.NewScheme()
+		gardencoreinstall.Install(scheme)
+		configinstall.Install(scheme)
+		v.decoder = serializer.NewCodecFactory(scheme, serializer.EnableStrict).UniversalDecoder()
+	}
+
+	return v, nil
+}
+
+// WithDecoder is an [Option], which configures the [Validator] with the given
+// [runtime.Decoder]. The decoder must be able to decode Shoot and
+// ExampleConfig resources into their internal versions.
+func WithDecoder(d runtime.Decoder) Option {
+	opt := func(v *Validator) error {
+		v.decoder = d
+
+		return nil
+	}
+
+	return opt
+}
+
+// ValidateFile validates the manifests in the file with the given path. The
+// special path "-" refers to the standard input.
+func (v *Validator) ValidateFile(path string) ([]Problem, error) {
+	if path == "-" {
+		return v.Validate("<stdin>", os.Stdin)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // reading user-provided manifests is intended
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Validate(path, bytes.NewReader(data))
+}
+
+// Validate validates the manifests read from the given [io.Reader]. The given
+// name is used to refer to the source of the manifests in the reported
+// problems. Documents, which are neither ExampleConfig nor Shoot resources,
+// are ignored.
+func (v *Validator) Validate(name string, r io.Reader) ([]Problem, error) {
+	problems := make([]Problem, 0)
+	dec := yaml.NewDecoder(r)
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		if len(node.Content) == 0 {
+			continue
+		}
+
+		doc := document{file: name, root: node.Content[0]}
+		problems = append(problems, v.validateDocument(doc)...)
+	}
+
+	return problems, nil
+}
+
+// validateDocument validates a single YAML document.
+func (v *Validator) validateDocument(doc document) []Problem {
+	data, err := doc.json()
+	if err != nil {
+		return []Problem{doc.problem("", err.Error())}
+	}
+
+	var typeMeta runtime.TypeMeta
+	if err := json.Unmarshal(data, &typeMeta); err != nil {
+		return []Problem{doc.problem("", err.Error())}
+	}
+
+	gvk := schema.FromAPIVersionAndKind(typeMeta.APIVersion, typeMeta.Kind)
+	switch {
+	case gvk.Group == config.GroupName && gvk.Kind == "ExampleConfig":
+		_, problems := v.validateConfig(doc, nil, data)
+
+		return problems
+	case gvk.Group == core.GroupName && gvk.Kind == "Shoot":
+		return v.validateShoot(doc, data)
+	default:
+		return nil
+	}
+}
+
+// validateShoot validates the configuration of the extension from the Shoot
+// manifest in the given document.
+func (v *Validator) validateShoot(doc document, data []byte) []Problem {
+	var shoot core.Shoot
+	if err := runtime.DecodeInto(v.decoder, data, &shoot); err != nil {
+		return doc.decodingProblems(nil, err)
+	}
+
+	problems := make([]Problem, 0)
+	for i, ext := range shoot.Spec.Extensions {
+		if ext.Type != v.extensionType {
+			continue
+		}
+
+		// Extension is disabled, nothing to validate
+		if ext.Disabled != nil && *ext.Disabled {
+			continue
+		}
+
+		path := field.NewPath("spec", "extensions").Index(i).Child("providerConfig")
+		if ext.ProviderConfig == nil {
+			err := field.Required(path, fmt.Sprintf("no provider config specified for %s", v.extensionType))
+			problems = append(problems, doc.fieldProblem(nil, err))
+
+			continue
+		}
+
+		cfg, cfgProblems := v.validateConfig(doc, path, ext.ProviderConfig.Raw)
+		problems = append(problems, cfgProblems...)
+		if cfg == nil {
+			continue
+		}
+
+		for _, err := range admissionvalidator.ValidateSecretRefs(*cfg, shoot.Spec.Resources) {
+			problems = append(problems, doc.fieldProblem(path, err))
+		}
+	}
+
+	return problems
+}
+
+// validateConfig decodes and validates the given ExampleConfig data, which is
+// located at the given path in the document. The decoded config is returned,
+// unless it failed to decode.
+func (v *Validator) validateConfig(doc document, path *field.Path, data []byte) (*config.ExampleConfig, []Problem) {
+	var cfg config.ExampleConfig
+	if err := runtime.DecodeInto(v.decoder, data, &cfg); err != nil {
+		return nil, doc.decodingProblems(path, err)
+	}
+
+	err := validation.Validate(cfg)
+	if err == nil {
+		return &cfg, nil
+	}
+
+	errs := []error{err}
+	var agg utilerrors.Aggregate
+	if errors.As(err, &agg) {
+		errs = agg.Errors()
+	}
+
+	problems := make([]Problem, 0, len(errs))
+	for _, err := range errs {
+		var fieldErr *field.Error
+		if errors.As(err, &fieldErr) {
+			problems = append(problems, doc.fieldProblem(path, fieldErr))
+
+			continue
+		}
+		problems = append(problems, doc.problemAt(path, "", err.Error()))
+	}
+
+	return &cfg, problems
+}
+
+// document is a single YAML document from a manifest file.
+type document struct {
+	// file is the name of the file, which contains the document.
+	file string
+
+	// root is the root node of the document.
+	root *yaml.Node
+}
+
+// json returns the JSON representation of the document.
+func (d document) json() ([]byte, error) {
+	var obj any
+	if err := d.root.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(obj)
+}
+
+// problem returns a [Problem] for the given field path of the document.
+func (d document) problem(fieldPath, detail string) Problem {
+	return Problem{
+		File:   d.file,
+		Line:   d.line(fieldPath),
+		Field:  fieldPath,
+		Detail: detail,
+	}
+}
+
+// problemAt returns a [Problem] for the given field path relative to the
+// given base path of the document.
+func (d document) problemAt(base *field.Path, fieldPath, detail string) Problem {
+	return d.problem(joinPath(base, fieldPath), detail)
+}
+
+// fieldProblem returns a [Problem] for the given [field.Error], which
+// refers to a field relative to the given base path of the document.
+func (d document) fieldProblem(base *field.Path, err *field.Error) Problem {
+	return d.problemAt(base, err.Field, err.ErrorBody())
+}
+
+// decodingProblems returns the problems for the given decoding error of an
+// object located at the given base path of the document. Unknown and
+// duplicate fields reported by the strict decoder are reported separately.
+func (d document) decodingProblems(base *field.Path, err error) []Problem {
+	strictErr, ok := runtime.AsStrictDecodingError(err)
+	if !ok {
+		return []Problem{d.problemAt(base, "", err.Error())}
+	}
+
+	problems := make([]Problem, 0, len(strictErr.Errors()))
+	for _, err := range strictErr.Errors() {
+		matches := strictErrorRegexp.FindStringSubmatch(err.Error())
+		if matches == nil {
+			problems = append(problems, d.problemAt(base, "", err.Error()))
+
+			continue
+		}
+		problems = append(problems, d.problemAt(base, matches[1], strings.TrimSuffix(err.Error(), fmt.Sprintf(" %q", matches[1]))))
+	}
+
+	return problems
+}
+
+// line returns the line of the node with the given field path, e.g.
+// "spec.extensions[0].providerConfig". If the field does not exist, the line
+// of its closest existing parent is returned.
+func (d document) line(fieldPath string) int {
+	node := d.root
+	line := node.Line
+
+	for _, segment := range splitPath(fieldPath) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			found := false
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == segment {
+					line = node.Content[i].Line
+					node = node.Content[i+1]
+					found = true
+
+					break
+				}
+			}
+			if !found {
+				return line
+			}
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return line
+			}
+			node = node.Content[idx]
+			line = node.Line
+		default:
+			return line
+		}
+	}
+
+	return line
+}
+
+// splitPath splits the given field path into its segments, e.g.
+// "spec.secretRefs[1].name" is split into "spec", "secretRefs", "1" and
+// "name".
+func splitPath(fieldPath string) []string {
+	return strings.FieldsFunc(fieldPath, func(r rune) bool {
+		return r == '.' || r == '[' || r == ']'
+	})
+}
+
+// joinPath joins the given field path with the given base path.
+func joinPath(base *field.Path, fieldPath string) string {
+	switch {
+	case base == nil:
+		return fieldPath
+	case fieldPath == "":
+		return base.String()
+	default:
+		return base.String() + "." + fieldPath
+	}
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"gardener-extension-example/pkg/manifest"
+)
+
+const invalidManifests = `apiVersion: example.extensions.gardener.cloud/v1alpha1
+kind: ExampleConfig
+spec:
+  fooo: bar
+---
+apiVersion: core.gardener.cloud/v1beta1
+kind: Shoot
+metadata:
+  name: local
+  namespace: garden-local
+spec:
+  extensions:
+    - type: example
+      providerConfig:
+        apiVersion: example.extensions.gardener.cloud/v1alpha1
+        kind: ExampleConfig
+        spec:
+          foo: ""
+          secretRefs:
+            - name: api-token
+            - name: missing
+  resources:
+    - name: api-token
+      resourceRef:
+        apiVersion: v1
+        kind: ConfigMap
+        name: api-token
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`
+
+var _ = Describe("Manifest", func() {
+	var validator *manifest.Validator
+
+	BeforeEach(func() {
+		var err error
+		validator, err = manifest.New()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should successfully validate the example shoot", func() {
+		problems, err := validator.ValidateFile("../../examples/shoot.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(BeEmpty())
+	})
+
+	It("should report problems with file and line context", func() {
+		problems, err := validator.Validate("manifests.yaml", strings.NewReader(invalidManifests))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(ConsistOf(
+			manifest.Problem{
+				File:   "manifests.yaml",
+				Line:   4,
+				Field:  "spec.fooo",
+				Detail: "unknown field",
+			},
+			manifest.Problem{
+				File:   "manifests.yaml",
+				Line:   18,
+				Field:  "spec.extensions[0].providerConfig.spec.foo",
+				Detail: "Required value: empty value specified",
+			},
+			manifest.Problem{
+				File:   "manifests.yaml",
+				Line:   20,
+				Field:  "spec.extensions[0].providerConfig.spec.secretRefs[0].name",
+				Detail: `Invalid value: "api-token": referenced resource is not a secret`,
+			},
+			manifest.Problem{
+				File:   "manifests.yaml",
+				Line:   21,
+				Field:  "spec.extensions[0].providerConfig.spec.secretRefs[1].name",
+				Detail: `Not found: "missing"`,
+			},
+		))
+		Expect(problems[0].String()).To(Equal("manifests.yaml:4: spec.fooo: unknown field"))
+	})
+
+	It("should report shoots without provider config", func() {
+		shoot := `apiVersion: core.gardener.cloud/v1beta1
+kind: Shoot
+metadata:
+  name: local
+spec:
+  extensions:
+    - type: example
+`
+		problems, err := validator.Validate("shoot.yaml", strings.NewReader(shoot))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].Line).To(Equal(7))
+		Expect(problems[0].Field).To(Equal("spec.extensions[0].providerConfig"))
+	})
+
+	It("should fail on malformed YAML", func() {
+		_, err := validator.Validate("broken.yaml", strings.NewReader("spec: [foo"))
+		Expect(err).To(MatchError(ContainSubstring("failed to parse broken.yaml")))
+	})
+})
@@ -35,6 +35,120 @@ var (
 		},
 		[]string{"cluster", "operation"},
 	)
+
+	// ActuatorReconcileTotal is a metric, which increments each time our
+	// extension actuator reconciles an extension resource. The type label
+	// is either "full" for full reconciliations, or "skipped" for
+	// reconciliations, which were skipped because none of their inputs
+	// changed.
+	ActuatorReconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "actuator_reconcile_total",
+			Help:      "Total number of full and skipped reconciliations by our extension actuator",
+		},
+		[]string{"cluster", "type"},
+	)
+
+	// GarbageCollectorOrphansFound is a metric, which provides the number
+	// of orphaned objects found by the last run of the garbage collector.
+	GarbageCollectorOrphansFound = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "gc_orphans_found",
+			Help:      "Number of orphaned objects found by the last run of the garbage collector",
+		},
+		[]string{"kind"},
+	)
+
+	// GarbageCollectorOrphansRemovedTotal is a metric, which increments
+	// each time the garbage collector deletes an orphaned object.
+	GarbageCollectorOrphansRemovedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "gc_orphans_removed_total",
+			Help:      "Total number of orphaned objects deleted by the garbage collector",
+		},
+		[]string{"kind"},
+	)
+
+	// ShardingMembers is a metric, which provides the number of controller
+	// replicas with a live lease, which share the reconciliation of the
+	// shoot namespaces.
+	ShardingMembers = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "sharding_members",
+			Help:      "Number of controller replicas sharing the reconciliation of the shoot namespaces",
+		},
+	)
+
+	// ShardingOwnedNamespaces is a metric, which provides the number of
+	// shoot namespaces owned by the shard of this replica.
+	ShardingOwnedNamespaces = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "sharding_owned_namespaces",
+			Help:      "Number of shoot namespaces owned by the shard of this replica",
+		},
+	)
+
+	// ShardingRebalancesTotal is a metric, which increments each time the
+	// shards are rebalanced, because replicas came or went.
+	ShardingRebalancesTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "sharding_rebalances_total",
+			Help:      "Total number of times the shards have been rebalanced",
+		},
+	)
+
+	// HeartbeatLastRenewalTimestampSeconds is a metric, which provides the
+	// Unix time of the last successful renewal of the heartbeat lease.
+	HeartbeatLastRenewalTimestampSeconds = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "heartbeat_last_renewal_timestamp_seconds",
+			Help:      "Unix time of the last successful renewal of the heartbeat lease",
+		},
+	)
+
+	// HeartbeatRenewalErrorsTotal is a metric, which increments each time
+	// the heartbeat lease could not be renewed.
+	HeartbeatRenewalErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "heartbeat_renewal_errors_total",
+			Help:      "Total number of failed renewals of the heartbeat lease",
+		},
+	)
+
+	// AdmissionRequestsTotal is a metric, which increments each time a
+	// validator of the extension handles an admission request. The resource
+	// label is either "shoot" or "extension", the result label is either
+	// "allowed" or "denied", and the reason label is the category of the
+	// validation error, or "none" for allowed requests.
+	AdmissionRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "admission_requests_total",
+			Help:      "Total number of admission requests handled by the validators of the extension",
+		},
+		[]string{"resource", "operation", "result", "reason"},
+	)
+
+	// AdmissionDurationSeconds is a metric, which tracks the duration of
+	// the validation of admission requests by the validators of the
+	// extension.
+	AdmissionDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "admission_duration_seconds",
+			Help:      "Duration of the validation of admission requests by the validators of the extension",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 4, 8),
+		},
+		[]string{"resource", "operation", "result"},
+	)
 )
 
 // init registers our custom metrics with the default controller-runtime registry.
@@ -42,5 +156,15 @@ func init() {
 	ctrlmetrics.Registry.MustRegister(
 		ActuatorOperationTotal,
 		ActuatorOperationDurationSeconds,
+		ActuatorReconcileTotal,
+		GarbageCollectorOrphansFound,
+		GarbageCollectorOrphansRemovedTotal,
+		ShardingMembers,
+		ShardingOwnedNamespaces,
+		ShardingRebalancesTotal,
+		HeartbeatLastRenewalTimestampSeconds,
+		HeartbeatRenewalErrorsTotal,
+		AdmissionRequestsTotal,
+		AdmissionDurationSeconds,
 	)
 }
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mgr
+
+import (
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StripManagedFields is a [toolscache.TransformFunc], which removes the
+// managed fields and the last applied configuration annotation from objects
+// before they are stored in the cache. Both are usually not needed by
+// controllers, but may easily account for half of the size of an object.
+//
+// Objects, whose managed fields are inspected by the controller, e.g. in
+// order to migrate them to another field manager, must not be transformed.
+func StripManagedFields(obj any) (any, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// Tombstones of deleted objects are passed as is.
+		return obj, nil
+	}
+
+	accessor.SetManagedFields(nil)
+	if annotations := accessor.GetAnnotations(); annotations != nil {
+		delete(annotations, corev1.LastAppliedConfigAnnotation)
+		accessor.SetAnnotations(annotations)
+	}
+
+	return obj, nil
+}
+
+// byObject returns the key and the [cache.ByObject] settings of the cache
+// options for objects of the same type as the given object.
+func (m *mgr) byObject(obj client.Object) (client.Object, cache.ByObject) {
+	for key, opts := range m.cacheOpts.ByObject {
+		if reflect.TypeOf(key) == reflect.TypeOf(obj) {
+			return key, opts
+		}
+	}
+
+	return obj, cache.ByObject{}
+}
+
+// setByObject sets the [cache.ByObject] settings of the cache options for
+// objects of the same type as the given object.
+func (m *mgr) setByObject(obj client.Object, opts cache.ByObject) {
+	if m.cacheOpts.ByObject == nil {
+		m.cacheOpts.ByObject = make(map[client.Object]cache.ByObject)
+	}
+
+	key, _ := m.byObject(obj)
+	m.cacheOpts.ByObject[key] = opts
+}
+
+// WithCacheByObject is an [Option], which configures the cache of the
+// [manager.Manager] with the given [cache.ByObject] settings for objects of
+// the same type as the given object, e.g. in order to restrict the informer
+// to certain namespaces. Settings from previous options for the same type are
+// replaced.
+func WithCacheByObject(obj client.Object, opts cache.ByObject) Option {
+	opt := func(m *mgr) error {
+		if obj == nil {
+			return fmt.Errorf("%w: no object specified for cache settings", ErrInvalidManager)
+		}
+		m.setByObject(obj, opts)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithCacheLabelSelector is an [Option], which restricts the informer for
+// objects of the same type as the given object to objects matching the given
+// [labels.Selector]. Objects, which do not match the selector, can neither be
+// read from the cache, nor trigger a reconciliation.
+//
+// Kubernetes does not support selecting objects by the labels of their
+// namespace, so the objects themselves must be labelled accordingly.
+func WithCacheLabelSelector(obj client.Object, selector labels.Selector) Option {
+	opt := func(m *mgr) error {
+		if obj == nil {
+			return fmt.Errorf("%w: no object specified for cache label selector", ErrInvalidManager)
+		}
+		_, opts := m.byObject(obj)
+		opts.Label = selector
+		m.setByObject(obj, opts)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithCacheTransform is an [Option], which configures the cache of the
+// [manager.Manager] to transform objects of the same type as the given object
+// with the given [toolscache.TransformFunc] before they are stored, e.g.
+// [StripManagedFields].
+func WithCacheTransform(obj client.Object, transform toolscache.TransformFunc) Option {
+	opt := func(m *mgr) error {
+		if obj == nil {
+			return fmt.Errorf("%w: no object specified for cache transform", ErrInvalidManager)
+		}
+		_, opts := m.byObject(obj)
+		opts.Transform = transform
+		m.setByObject(obj, opts)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithDefaultCacheTransform is an [Option], which configures the cache of the
+// [manager.Manager] to transform objects with the given
+// [toolscache.TransformFunc] before they are stored, unless another transform
+// has been configured for their type via [WithCacheTransform].
+func WithDefaultCacheTransform(transform toolscache.TransformFunc) Option {
+	opt := func(m *mgr) error {
+		m.cacheOpts.DefaultTransform = transform
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithUncachedObjects is an [Option], which configures the client of the
+// [manager.Manager] to read objects of the same types as the given objects
+// directly from the API server instead of the cache. No informer is started
+// for these types, unless they are watched, which may be done via a
+// metadata-only informer using
+// [k8s.io/apimachinery/pkg/apis/meta/v1.PartialObjectMetadata].
+func WithUncachedObjects(objs ...client.Object) Option {
+	opt := func(m *mgr) error {
+		if m.clientOpts.Cache == nil {
+			m.clientOpts.Cache = &client.CacheOptions{}
+		}
+		m.clientOpts.Cache.DisableFor = append(m.clientOpts.Cache.DisableFor, objs...)
+
+		return nil
+	}
+
+	return opt
+}
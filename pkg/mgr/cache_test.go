@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mgr_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gmeasure"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"gardener-extension-example/pkg/mgr"
+)
+
+var _ = Describe("Cache", func() {
+	It("should strip managed fields and the last applied configuration", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				Annotations: map[string]string{
+					corev1.LastAppliedConfigAnnotation: "{}",
+					"foo":                              "bar",
+				},
+				ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			},
+		}
+
+		obj, err := mgr.StripManagedFields(secret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj).To(BeIdenticalTo(secret))
+		Expect(secret.ManagedFields).To(BeEmpty())
+		Expect(secret.Annotations).To(Equal(map[string]string{"foo": "bar"}))
+	})
+
+	It("should fail to configure the cache without an object", func() {
+		m, err := mgr.New(
+			mgr.WithConfig(cfg),
+			mgr.WithCacheLabelSelector(nil, labels.Everything()),
+		)
+
+		Expect(err).To(MatchError(mgr.ErrInvalidManager))
+		Expect(m).To(BeNil())
+	})
+
+	Context("with a large number of secrets", Ordered, func() {
+		const (
+			numSecrets = 500
+			dataSize   = 8 << 10
+		)
+
+		var namespace string
+
+		// cachedSize starts the cache of a new manager with the given options,
+		// waits for the given list of objects to be cached, and returns the
+		// size of the cached objects when serialized, and the growth of the
+		// heap.
+		cachedSize := func(list client.ObjectList, opts ...mgr.Option) (int, uint64) {
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+
+			m, err := mgr.New(append([]mgr.Option{
+				mgr.WithConfig(cfg),
+				mgr.WithAddToScheme(clientgoscheme.AddToScheme),
+			}, opts...)...)
+			Expect(err).NotTo(HaveOccurred())
+
+			cacheCtx, cacheCancel := context.WithCancel(ctx)
+			defer cacheCancel()
+
+			go func() {
+				defer GinkgoRecover()
+				Expect(m.GetCache().Start(cacheCtx)).To(Succeed())
+			}()
+
+			Eventually(func(g Gomega) {
+				g.Expect(m.GetCache().List(cacheCtx, list, client.InNamespace(namespace))).To(Succeed())
+				g.Expect(meta.LenList(list)).To(Equal(numSecrets))
+			}).Should(Succeed())
+
+			runtime.GC()
+			runtime.ReadMemStats(&after)
+			runtime.KeepAlive(m)
+
+			data, err := json.Marshal(list)
+			Expect(err).NotTo(HaveOccurred())
+
+			return len(data), after.HeapAlloc - min(before.HeapAlloc, after.HeapAlloc)
+		}
+
+		BeforeAll(func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "cache-"}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			namespace = ns.Name
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(ctx, ns)).To(Succeed())
+			})
+
+			for i := range numSecrets {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      fmt.Sprintf("ref-secret-%d", i),
+						Namespace: namespace,
+						Annotations: map[string]string{
+							corev1.LastAppliedConfigAnnotation: string(bytes.Repeat([]byte("a"), dataSize)),
+						},
+					},
+					Data: map[string][]byte{
+						"data": bytes.Repeat([]byte("b"), dataSize),
+					},
+				}
+				Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+			}
+		})
+
+		It("should reduce the memory used by metadata-only informers and transforms", func() {
+			experiment := gmeasure.NewExperiment("Cache memory")
+			AddReportEntry(experiment.Name, experiment)
+
+			fullSize, fullHeap := cachedSize(&corev1.SecretList{})
+			experiment.RecordValue("full objects", float64(fullHeap)/(1<<20), gmeasure.Units("MiB"))
+
+			metadata := &metav1.PartialObjectMetadataList{}
+			metadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("SecretList"))
+			reducedSize, reducedHeap := cachedSize(metadata, mgr.WithDefaultCacheTransform(mgr.StripManagedFields))
+			experiment.RecordValue("metadata without managed fields", float64(reducedHeap)/(1<<20), gmeasure.Units("MiB"))
+
+			for _, item := range metadata.Items {
+				Expect(item.ManagedFields).To(BeEmpty())
+				Expect(item.Annotations).NotTo(HaveKey(corev1.LastAppliedConfigAnnotation))
+			}
+
+			Expect(fullSize).To(BeNumerically(">", 2*numSecrets*dataSize))
+			Expect(reducedSize).To(BeNumerically("<", fullSize/10))
+			Expect(reducedHeap).To(BeNumerically("<", fullHeap))
+		})
+	})
+})
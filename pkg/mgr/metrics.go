@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package mgr
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// metricsAuthFilterProvider returns a filter provider for the metrics server,
+// which authenticates and authorizes requests via
+// [filters.WithAuthenticationAndAuthorization] against the cluster of the
+// given [rest.Config], or the cluster of the manager, if it is nil.
+func metricsAuthFilterProvider(cfg *rest.Config) func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+	provider := func(c *rest.Config, httpClient *http.Client) (metricsserver.Filter, error) {
+		if cfg != nil {
+			client, err := rest.HTTPClientFor(cfg)
+			if err != nil {
+				return nil, err
+			}
+			c, httpClient = cfg, client
+		}
+
+		return filters.WithAuthenticationAndAuthorization(c, httpClient)
+	}
+
+	return provider
+}
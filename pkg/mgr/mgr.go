@@ -8,6 +8,7 @@ package mgr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -29,6 +30,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// ErrInvalidManager is an error, which is returned when attempting to create a
+// [manager.Manager], but the configuration was found to be invalid.
+var ErrInvalidManager = errors.New("invalid manager config")
+
 // mgr is a wrapper around [manager.Manager] with functional options API.
 type mgr struct {
 	scheme                  *runtime.Scheme
@@ -36,12 +41,20 @@ type mgr struct {
 	installSchemes          []func(s *runtime.Scheme)
 	restConfig              *rest.Config
 	metricsServerOpts       metricsserver.Options
+	metricsAuthEnabled      bool
+	metricsAuthConfig       *rest.Config
 	healthProbeAddr         string
 	pprofAddr               string
 	leaderElectionEnabled   bool
 	leaderElectionID        string
 	leaderElectionNamespace string
 	leaderElectionConfig    *rest.Config
+	leaderElectionLock      string
+	leaseDuration           *time.Duration
+	renewDeadline           *time.Duration
+	retryPeriod             *time.Duration
+	releaseOnCancel         bool
+	gracefulShutdownTimeout *time.Duration
 	webhookServer           webhook.Server
 	baseCtxFunc             manager.BaseContextFunc
 	controllerOpts          controllerconfig.Controller
@@ -58,11 +71,12 @@ type mgr struct {
 // New creates a new [manager.Manager] with the given options.
 func New(opts ...Option) (manager.Manager, error) {
 	m := &mgr{
-		scheme:            runtime.NewScheme(),
-		addToSchemes:      make([]func(s *runtime.Scheme) error, 0),
-		installSchemes:    make([]func(s *runtime.Scheme), 0),
-		metricsServerOpts: metricsserver.Options{},
-		baseCtxFunc:       context.Background,
+		scheme:             runtime.NewScheme(),
+		addToSchemes:       make([]func(s *runtime.Scheme) error, 0),
+		installSchemes:     make([]func(s *runtime.Scheme), 0),
+		metricsServerOpts:  metricsserver.Options{},
+		leaderElectionLock: resourcelock.LeasesResourceLock,
+		baseCtxFunc:        context.Background,
 		controllerOpts: controllerconfig.Controller{
 			MaxConcurrentReconciles: 5,
 			ReconciliationTimeout:   controllerutils.DefaultReconciliationTimeout,
@@ -80,6 +94,15 @@ func New(opts ...Option) (manager.Manager, error) {
 		}
 	}
 
+	// Protect the metrics server with authentication and authorization,
+	// which would otherwise pass bearer tokens in plain text.
+	if m.metricsAuthEnabled {
+		if !m.metricsServerOpts.SecureServing {
+			return nil, fmt.Errorf("%w: metrics authorization requires secure serving", ErrInvalidManager)
+		}
+		m.metricsServerOpts.FilterProvider = metricsAuthFilterProvider(m.metricsAuthConfig)
+	}
+
 	// Register additional schemes
 	for _, addToScheme := range m.addToSchemes {
 		if err := addToScheme(m.scheme); err != nil {
@@ -106,21 +129,26 @@ func New(opts ...Option) (manager.Manager, error) {
 	crMgr, err := manager.New(
 		m.restConfig,
 		manager.Options{
-			Scheme:                     m.scheme,
-			Metrics:                    m.metricsServerOpts,
-			HealthProbeBindAddress:     m.healthProbeAddr,
-			LeaderElection:             m.leaderElectionEnabled,
-			LeaderElectionID:           m.leaderElectionID,
-			LeaderElectionNamespace:    m.leaderElectionNamespace,
-			LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
-			LeaderElectionConfig:       m.leaderElectionConfig,
-			BaseContext:                m.baseCtxFunc,
-			Controller:                 m.controllerOpts,
-			WebhookServer:              m.webhookServer,
-			Logger:                     m.logger,
-			PprofBindAddress:           m.pprofAddr,
-			Client:                     m.clientOpts,
-			Cache:                      m.cacheOpts,
+			Scheme:                        m.scheme,
+			Metrics:                       m.metricsServerOpts,
+			HealthProbeBindAddress:        m.healthProbeAddr,
+			LeaderElection:                m.leaderElectionEnabled,
+			LeaderElectionID:              m.leaderElectionID,
+			LeaderElectionNamespace:       m.leaderElectionNamespace,
+			LeaderElectionResourceLock:    m.leaderElectionLock,
+			LeaderElectionConfig:          m.leaderElectionConfig,
+			LeaderElectionReleaseOnCancel: m.releaseOnCancel,
+			LeaseDuration:                 m.leaseDuration,
+			RenewDeadline:                 m.renewDeadline,
+			RetryPeriod:                   m.retryPeriod,
+			GracefulShutdownTimeout:       m.gracefulShutdownTimeout,
+			BaseContext:                   m.baseCtxFunc,
+			Controller:                    m.controllerOpts,
+			WebhookServer:                 m.webhookServer,
+			Logger:                        m.logger,
+			PprofBindAddress:              m.pprofAddr,
+			Client:                        m.clientOpts,
+			Cache:                         m.cacheOpts,
 		},
 	)
 	if err != nil {
@@ -232,6 +260,85 @@ func WithMetricsAddress(addr string) Option {
 	return opt
 }
 
+// WithMetricsSecureServing is an [Option], which configures the
+// [manager.Manager] to serve metrics via HTTPS, if set to true. Unless a
+// certificate is provided via [WithMetricsCertDir], a self-signed certificate
+// is generated on startup.
+func WithMetricsSecureServing(enable bool) Option {
+	opt := func(m *mgr) error {
+		m.metricsServerOpts.SecureServing = enable
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithMetricsCertDir is an [Option], which configures the [manager.Manager] to
+// serve metrics via HTTPS with the certificate and key from the given
+// directory. The certificate is reloaded, whenever it is rotated.
+func WithMetricsCertDir(dir string) Option {
+	opt := func(m *mgr) error {
+		m.metricsServerOpts.CertDir = dir
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithMetricsCertName is an [Option], which configures the [manager.Manager]
+// with the file name of the metrics server certificate.
+func WithMetricsCertName(name string) Option {
+	opt := func(m *mgr) error {
+		m.metricsServerOpts.CertName = name
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithMetricsKeyName is an [Option], which configures the [manager.Manager]
+// with the file name of the metrics server key.
+func WithMetricsKeyName(name string) Option {
+	opt := func(m *mgr) error {
+		m.metricsServerOpts.KeyName = name
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithMetricsAuthorization is an [Option], which configures the
+// [manager.Manager] to authenticate and authorize requests to the metrics
+// server and its extra handlers via TokenReview and SubjectAccessReview, if
+// set to true, using the metrics filters of controller-runtime. It requires
+// [WithMetricsSecureServing].
+func WithMetricsAuthorization(enable bool) Option {
+	opt := func(m *mgr) error {
+		m.metricsAuthEnabled = enable
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithMetricsAuthorizationConfig is an [Option], which configures the
+// [manager.Manager] to review requests to the metrics server against the
+// cluster of the given [rest.Config] instead of the cluster of the manager.
+func WithMetricsAuthorizationConfig(cfg *rest.Config) Option {
+	opt := func(m *mgr) error {
+		m.metricsAuthConfig = cfg
+
+		return nil
+	}
+
+	return opt
+}
+
 // WithExtraMetricsHandler is an [Option], which configures the
 // [manager.Manager] to serve an extra handler via the metrics server.
 func WithExtraMetricsHandler(path string, handler http.Handler) Option {
@@ -292,6 +399,82 @@ func WithLeaderElectionConfig(cfg *rest.Config) Option {
 	return opt
 }
 
+// WithLeaderElectionResourceLock is an [Option], which configures the kind of
+// resource lock to use for leader election. By default leases are used.
+func WithLeaderElectionResourceLock(lock string) Option {
+	opt := func(m *mgr) error {
+		m.leaderElectionLock = lock
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithLeaseDuration is an [Option], which configures the duration, which
+// non-leader candidates wait before attempting to acquire the leadership,
+// after the leader stopped renewing the lease.
+func WithLeaseDuration(d time.Duration) Option {
+	opt := func(m *mgr) error {
+		m.leaseDuration = &d
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithRenewDeadline is an [Option], which configures the duration, for which
+// the leader retries to renew the lease, before giving up the leadership.
+func WithRenewDeadline(d time.Duration) Option {
+	opt := func(m *mgr) error {
+		m.renewDeadline = &d
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithRetryPeriod is an [Option], which configures the duration, which leader
+// election clients wait between attempts to acquire or renew the lease.
+func WithRetryPeriod(d time.Duration) Option {
+	opt := func(m *mgr) error {
+		m.retryPeriod = &d
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithLeaderElectionReleaseOnCancel is an [Option], which configures the
+// [manager.Manager] to release the leader election lease on shutdown, once all
+// runnables have stopped, if set to true. This lets the next leader take over
+// immediately instead of waiting for the lease to expire.
+func WithLeaderElectionReleaseOnCancel(release bool) Option {
+	opt := func(m *mgr) error {
+		m.releaseOnCancel = release
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithGracefulShutdownTimeout is an [Option], which configures the duration,
+// for which the [manager.Manager] waits for its runnables to stop on
+// shutdown. A negative duration waits forever.
+func WithGracefulShutdownTimeout(d time.Duration) Option {
+	opt := func(m *mgr) error {
+		m.gracefulShutdownTimeout = &d
+
+		return nil
+	}
+
+	return opt
+}
+
 // WithContext is an [Option], which configures the [manager.Manager] to use the
 // given [context.Context] as the base context.
 func WithContext(ctx context.Context) Option {
@@ -12,6 +12,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/component-base/config/v1alpha1"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -35,6 +36,17 @@ var _ = Describe("Manager", Ordered, func() {
 		Expect(m).To(BeNil())
 	})
 
+	It("should fail to create a manager with metrics authorization, but without secure serving", func() {
+		m, err := mgr.New(
+			mgr.WithMetricsAddress(":8080"),
+			mgr.WithMetricsAuthorization(true),
+		)
+
+		Expect(err).To(MatchError(mgr.ErrInvalidManager))
+		Expect(err).To(MatchError(ContainSubstring("metrics authorization requires secure serving")))
+		Expect(m).To(BeNil())
+	})
+
 	It("should successfully create a manager", func() {
 		extraHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		})
@@ -61,6 +73,12 @@ var _ = Describe("Manager", Ordered, func() {
 			mgr.WithLeaderElection(true),
 			mgr.WithLeaderElectionID("foobar"),
 			mgr.WithLeaderElectionNamespace("default"),
+			mgr.WithLeaderElectionResourceLock("leases"),
+			mgr.WithLeaseDuration(30 * time.Second),
+			mgr.WithRenewDeadline(20 * time.Second),
+			mgr.WithRetryPeriod(5 * time.Second),
+			mgr.WithLeaderElectionReleaseOnCancel(true),
+			mgr.WithGracefulShutdownTimeout(time.Minute),
 			mgr.WithContext(ctx),
 			mgr.WithMaxConcurrentReconciles(42),
 			mgr.WithReconciliationTimeout(3 * time.Minute),
@@ -72,6 +90,11 @@ var _ = Describe("Manager", Ordered, func() {
 			mgr.WithClientOptions(client.Options{HTTPClient: http.DefaultClient}),
 			mgr.WithConnectionConfiguration(&v1alpha1.ClientConnectionConfiguration{QPS: 100.0, Burst: 130}),
 			mgr.WithCacheOptions(cache.Options{HTTPClient: http.DefaultClient}),
+			mgr.WithCacheByObject(&corev1.ConfigMap{}, cache.ByObject{Namespaces: map[string]cache.Config{"default": {}}}),
+			mgr.WithCacheLabelSelector(&corev1.Secret{}, labels.SelectorFromSet(labels.Set{"foo": "bar"})),
+			mgr.WithCacheTransform(&corev1.Secret{}, mgr.StripManagedFields),
+			mgr.WithDefaultCacheTransform(mgr.StripManagedFields),
+			mgr.WithUncachedObjects(&corev1.Pod{}),
 			mgr.WithLogger(logger),
 			mgr.WithPprofAddress(":7070"),
 			mgr.WithRunnable(testRunnable),
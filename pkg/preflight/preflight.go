@@ -0,0 +1,391 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package preflight provides checks, which verify that the cluster provides
+// the CRDs, RBAC permissions and namespaces required by the extension before
+// it is started.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ErrInvalidChecker is an error, which is returned when creating a [Checker]
+// with invalid config settings.
+var ErrInvalidChecker = errors.New("invalid preflight checker")
+
+// ErrChecksFailed is an error, which is returned when preflight checks fail.
+var ErrChecksFailed = errors.New("preflight checks failed")
+
+// Status is the status of a preflight check.
+type Status string
+
+const (
+	// StatusPassed means that the check has passed.
+	StatusPassed Status = "PASS"
+	// StatusWarning means that the check could not be performed, e.g.
+	// because of missing permissions to perform the check itself.
+	StatusWarning Status = "WARN"
+	// StatusFailed means that the check has failed.
+	StatusFailed Status = "FAIL"
+)
+
+// Result is the result of a single preflight check.
+type Result struct {
+	// Check describes what has been checked.
+	Check string
+
+	// Status is the status of the check.
+	Status Status
+
+	// Message provides details about the status of the check.
+	Message string
+
+	// Hint describes how to fix a failed check.
+	Hint string
+}
+
+// Report is the report of all preflight checks.
+type Report struct {
+	// Results are the results of the preflight checks.
+	Results []Result
+}
+
+// Failed returns true, if any of the checks in the [Report] failed.
+func (r *Report) Failed() bool {
+	return slices.ContainsFunc(r.Results, func(res Result) bool {
+		return res.Status == StatusFailed
+	})
+}
+
+// add adds a new [Result] to the [Report].
+func (r *Report) add(status Status, check, message, hint string) {
+	res := Result{
+		Check:   check,
+		Status:  status,
+		Message: message,
+		Hint:    hint,
+	}
+	r.Results = append(r.Results, res)
+}
+
+// Write writes the human-readable [Report] to the given [io.Writer].
+func (r *Report) Write(w io.Writer) error {
+	failed := 0
+	for _, res := range r.Results {
+		if _, err := fmt.Fprintf(w, "[%s] %s: %s\n", res.Status, res.Check, res.Message); err != nil {
+			return err
+		}
+		if res.Status != StatusPassed && res.Hint != "" {
+			if _, err := fmt.Fprintf(w, "       hint: %s\n", res.Hint); err != nil {
+				return err
+			}
+		}
+		if res.Status == StatusFailed {
+			failed++
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d check(s), %d failed\n", len(r.Results), failed)
+
+	return err
+}
+
+// Log logs the checks of the [Report], which did not pass, with the given
+// [logr.Logger].
+func (r *Report) Log(logger logr.Logger) {
+	for _, res := range r.Results {
+		if res.Status == StatusPassed {
+			continue
+		}
+		logger.Info("preflight check did not pass", "status", res.Status, "check", res.Check, "message", res.Message, "hint", res.Hint)
+	}
+}
+
+// Checker performs preflight checks against a cluster.
+type Checker struct {
+	client      client.Client
+	crds        []schema.GroupVersionKind
+	permissions []Permission
+	namespaces  []string
+}
+
+// Option is a function, which configures the [Checker].
+type Option func(c *Checker) error
+
+// New creates a new [Checker], which uses the given [client.Client] to
+// perform the checks configured via the given options.
+func New(c client.Client, opts ...Option) (*Checker, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: no client specified", ErrInvalidChecker)
+	}
+
+	checker := &Checker{
+		client:      c,
+		crds:        make([]schema.GroupVersionKind, 0),
+		permissions: make([]Permission, 0),
+		namespaces:  make([]string, 0),
+	}
+
+	for _, opt := range opts {
+		if err := opt(checker); err != nil {
+			return nil, err
+		}
+	}
+
+	return checker, nil
+}
+
+// WithCRDs is an [Option], which configures the [Checker] to verify that the
+// resources of the given kinds are served by the cluster.
+func WithCRDs(gvks ...schema.GroupVersionKind) Option {
+	opt := func(c *Checker) error {
+		c.crds = append(c.crds, gvks...)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithPermissions is an [Option], which configures the [Checker] to verify
+// that the given permissions are granted.
+func WithPermissions(perms ...Permission) Option {
+	opt := func(c *Checker) error {
+		c.permissions = append(c.permissions, perms...)
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithNamespaces is an [Option], which configures the [Checker] to verify that
+// the given namespaces exist, e.g. the namespaces of leases.
+func WithNamespaces(namespaces ...string) Option {
+	opt := func(c *Checker) error {
+		for _, ns := range namespaces {
+			if ns != "" && !slices.Contains(c.namespaces, ns) {
+				c.namespaces = append(c.namespaces, ns)
+			}
+		}
+
+		return nil
+	}
+
+	return opt
+}
+
+// Run performs the preflight checks and returns the [Report]. An error is
+// returned only if checks could not be performed at all, e.g. because the
+// cluster is not reachable.
+func (c *Checker) Run(ctx context.Context) (*Report, error) {
+	report := &Report{
+		Results: make([]Result, 0),
+	}
+
+	for _, gvk := range c.crds {
+		c.checkCRD(report, gvk)
+	}
+
+	for _, ns := range c.namespaces {
+		if err := c.checkNamespace(ctx, report, ns); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, perm := range c.permissions {
+		if err := c.checkPermission(ctx, report, perm); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// Run runs the preflight checks of all given checkers and returns the merged
+// [Report].
+func Run(ctx context.Context, checkers ...*Checker) (*Report, error) {
+	report := &Report{
+		Results: make([]Result, 0),
+	}
+
+	for _, checker := range checkers {
+		r, err := checker.Run(ctx)
+		if err != nil {
+			return nil, err
+		}
+		report.Results = append(report.Results, r.Results...)
+	}
+
+	return report, nil
+}
+
+// checkCRD verifies that the resource of the given kind is served by the
+// cluster.
+func (c *Checker) checkCRD(report *Report, gvk schema.GroupVersionKind) {
+	check := fmt.Sprintf("kind %s in %s", gvk.Kind, gvk.GroupVersion())
+	mapping, err := c.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	switch {
+	case meta.IsNoMatchError(err):
+		report.add(StatusFailed, check, "resource is not served by the cluster", fmt.Sprintf("install the CustomResourceDefinition for %s", gvk.GroupKind()))
+	case err != nil:
+		report.add(StatusWarning, check, err.Error(), "verify that the API server is reachable and serves the discovery API")
+	default:
+		report.add(StatusPassed, check, fmt.Sprintf("served as %s", mapping.Resource.GroupResource()), "")
+	}
+}
+
+// checkNamespace verifies that the given namespace exists.
+func (c *Checker) checkNamespace(ctx context.Context, report *Report, name string) error {
+	check := fmt.Sprintf("namespace %s", name)
+	err := c.client.Get(ctx, client.ObjectKey{Name: name}, &corev1.Namespace{})
+	switch {
+	case apierrors.IsNotFound(err):
+		report.add(StatusFailed, check, "namespace does not exist", fmt.Sprintf("create namespace %s or configure a different lease namespace", name))
+	case apierrors.IsForbidden(err):
+		report.add(StatusWarning, check, "not allowed to get namespace", "grant get on namespaces to verify that the namespace exists")
+	case err != nil:
+		return fmt.Errorf("failed to get namespace %s: %w", name, err)
+	default:
+		report.add(StatusPassed, check, "namespace exists", "")
+	}
+
+	return nil
+}
+
+// checkPermission verifies that the given [Permission] is granted by
+// performing a [authorizationv1.SelfSubjectAccessReview].
+func (c *Checker) checkPermission(ctx context.Context, report *Report, perm Permission) error {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   perm.Namespace,
+				Verb:        perm.Verb,
+				Group:       perm.Group,
+				Resource:    perm.Resource,
+				Subresource: perm.Subresource,
+				Name:        perm.Name,
+			},
+		},
+	}
+
+	if err := c.client.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to review access for %s: %w", perm, err)
+	}
+
+	check := fmt.Sprintf("permission %s", perm)
+	switch {
+	case review.Status.Allowed:
+		report.add(StatusPassed, check, "allowed", "")
+	case review.Status.EvaluationError != "":
+		report.add(StatusFailed, check, fmt.Sprintf("denied: %s", review.Status.EvaluationError), perm.hint())
+	default:
+		report.add(StatusFailed, check, "denied", perm.hint())
+	}
+
+	return nil
+}
+
+// Permission is a permission, which is required by the extension.
+type Permission struct {
+	// Verb is the verb, e.g. get, list, create, etc.
+	Verb string
+
+	// Group is the API group of the resource.
+	Group string
+
+	// Resource is the name of the resource, e.g. secrets.
+	Resource string
+
+	// Subresource is the optional subresource, e.g. status.
+	Subresource string
+
+	// Namespace is the namespace, in which the permission is required. An
+	// empty namespace means all namespaces.
+	Namespace string
+
+	// Name is the optional name of the object.
+	Name string
+
+	// Source is the location of the RBAC rule, which grants the
+	// permission, e.g. a template of a Helm chart.
+	Source string
+}
+
+// String implements the [fmt.Stringer] interface.
+func (p Permission) String() string {
+	resource := p.Resource
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+	if p.Group != "" {
+		resource += "." + p.Group
+	}
+	if p.Name != "" {
+		resource += "/" + p.Name
+	}
+
+	namespace := "all namespaces"
+	if p.Namespace != "" {
+		namespace = "namespace " + p.Namespace
+	}
+
+	return fmt.Sprintf("%s %s in %s", p.Verb, resource, namespace)
+}
+
+// hint returns a hint about how to grant the [Permission].
+func (p Permission) hint() string {
+	group := p.Group
+	if group == "" {
+		group = `""`
+	}
+	resource := p.Resource
+	if p.Subresource != "" {
+		resource += "/" + p.Subresource
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "grant verb %q on resource %q in API group %s", p.Verb, resource, group)
+	if p.Source != "" {
+		fmt.Fprintf(&sb, ", see %s", p.Source)
+	}
+
+	return sb.String()
+}
+
+// newPermissions returns the permissions for the given verbs on the given
+// resource.
+func newPermissions(source, namespace, group, resource string, verbs ...string) []Permission {
+	result := make([]Permission, 0, len(verbs))
+	for _, verb := range verbs {
+		perm := Permission{
+			Verb:      verb,
+			Group:     group,
+			Resource:  resource,
+			Namespace: namespace,
+			Source:    source,
+		}
+		if before, after, ok := strings.Cut(resource, "/"); ok {
+			perm.Resource = before
+			perm.Subresource = after
+		}
+		result = append(result, perm)
+	}
+
+	return result
+}
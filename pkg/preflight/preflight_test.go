@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight_test
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"gardener-extension-example/pkg/preflight"
+)
+
+var _ = Describe("Preflight", func() {
+	const namespace = "gardener-extension-example"
+
+	var (
+		ctx    = context.Background()
+		denied []string
+	)
+
+	// newClient returns a fake client, which serves the given kinds and
+	// denies access reviews for the resources in denied.
+	newClient := func(gvks []schema.GroupVersionKind, objs ...client.Object) client.Client {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		for _, gvk := range gvks {
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+
+		return fake.NewClientBuilder().
+			WithRESTMapper(mapper).
+			WithObjects(objs...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					review, ok := obj.(*authorizationv1.SelfSubjectAccessReview)
+					if !ok {
+						return c.Create(ctx, obj, opts...)
+					}
+					attrs := review.Spec.ResourceAttributes
+					review.Status.Allowed = attrs != nil && !slices.Contains(denied, attrs.Resource)
+
+					return nil
+				},
+			}).
+			Build()
+	}
+
+	BeforeEach(func() {
+		denied = nil
+	})
+
+	It("should fail to create checker without client", func() {
+		_, err := preflight.New(nil)
+		Expect(err).To(MatchError(preflight.ErrInvalidChecker))
+	})
+
+	It("should pass when all requirements are met", func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+		c := newClient(preflight.ControllerCRDs(), ns)
+		checker, err := preflight.NewControllerChecker(c, namespace, namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := checker.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Failed()).To(BeFalse())
+		for _, res := range report.Results {
+			Expect(res.Status).To(Equal(preflight.StatusPassed), res.Check)
+		}
+	})
+
+	It("should fail when a CRD is not served", func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+		c := newClient([]schema.GroupVersionKind{
+			extensionsv1alpha1.SchemeGroupVersion.WithKind("Extension"),
+			extensionsv1alpha1.SchemeGroupVersion.WithKind("Cluster"),
+		}, ns)
+		checker, err := preflight.NewControllerChecker(c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := checker.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Failed()).To(BeTrue())
+
+		failed := failedResults(report)
+		Expect(failed).To(HaveLen(1))
+		Expect(failed[0].Check).To(ContainSubstring("ManagedResource"))
+		Expect(failed[0].Check).To(ContainSubstring(resourcesv1alpha1.SchemeGroupVersion.String()))
+	})
+
+	It("should fail when a lease namespace does not exist", func() {
+		c := newClient(preflight.ControllerCRDs())
+		checker, err := preflight.NewControllerChecker(c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := checker.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		failed := failedResults(report)
+		Expect(failed).To(HaveLen(1))
+		Expect(failed[0].Check).To(Equal("namespace " + namespace))
+	})
+
+	It("should point to the chart when a permission is denied", func() {
+		denied = []string{"managedresources"}
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}
+		c := newClient(preflight.ControllerCRDs(), ns)
+		checker, err := preflight.NewControllerChecker(c, namespace)
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := checker.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		failed := failedResults(report)
+		Expect(failed).NotTo(BeEmpty())
+		for _, res := range failed {
+			Expect(res.Check).To(ContainSubstring("managedresources.resources.gardener.cloud"))
+			Expect(res.Hint).To(ContainSubstring("charts/controller/templates/clusterrole.yaml"))
+		}
+	})
+
+	It("should write a human-readable report", func() {
+		c := newClient(nil)
+		checker, err := preflight.New(
+			c,
+			preflight.WithCRDs(preflight.WebhookCRDs()...),
+			preflight.WithPermissions(preflight.WebhookPermissions("example")[0]),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		report, err := checker.Run(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		var buf bytes.Buffer
+		Expect(report.Write(&buf)).To(Succeed())
+		Expect(buf.String()).To(Equal(
+			"[FAIL] kind Shoot in core.gardener.cloud/v1beta1: resource is not served by the cluster\n" +
+				"       hint: install the CustomResourceDefinition for Shoot.core.gardener.cloud\n" +
+				"[PASS] permission get shoots.core.gardener.cloud in all namespaces: allowed\n" +
+				"2 check(s), 1 failed\n",
+		))
+	})
+})
+
+// failedResults returns the failed results of the given report.
+func failedResults(report *preflight.Report) []preflight.Result {
+	result := make([]preflight.Result, 0)
+	for _, res := range report.Results {
+		if res.Status == preflight.StatusFailed {
+			result = append(result, res)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"slices"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// controllerClusterRole is the template of the cluster role of the
+	// controller.
+	controllerClusterRole = "charts/controller/templates/clusterrole.yaml"
+	// controllerRole is the template of the role of the controller in its
+	// own namespace.
+	controllerRole = "charts/controller/templates/role.yaml"
+	// webhookClusterRole is the template of the cluster role of the
+	// webhook in the garden cluster.
+	webhookClusterRole = "charts/admission-virtual/templates/clusterrole.yaml"
+	// webhookRole is the template of the role of the webhook in its own
+	// namespace in the runtime cluster.
+	webhookRole = "charts/admission-runtime/templates/role.yaml"
+	// webhookRuntimeClusterRole is the template of the cluster role of the
+	// webhook in the runtime cluster.
+	webhookRuntimeClusterRole = "charts/admission-runtime/templates/clusterrole.yaml"
+)
+
+// ControllerCRDs returns the kinds of resources, which must be served by the
+// seed cluster for the controller.
+func ControllerCRDs() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		extensionsv1alpha1.SchemeGroupVersion.WithKind("Extension"),
+		extensionsv1alpha1.SchemeGroupVersion.WithKind("Cluster"),
+		resourcesv1alpha1.SchemeGroupVersion.WithKind("ManagedResource"),
+	}
+}
+
+// ControllerPermissions returns the permissions required by the controller in
+// the seed cluster. The given namespaces are the namespaces of the controller,
+// in which leases and events are managed.
+//
+// TODO(user): keep the permissions in sync with the RBAC of the controller
+func ControllerPermissions(namespaces ...string) []Permission {
+	perms := slices.Concat(
+		newPermissions(controllerClusterRole, "", extensionsv1alpha1.SchemeGroupVersion.Group, "clusters", "get", "list", "watch"),
+		newPermissions(controllerClusterRole, "", extensionsv1alpha1.SchemeGroupVersion.Group, "extensions", "get", "list", "watch", "patch", "update"),
+		newPermissions(controllerClusterRole, "", extensionsv1alpha1.SchemeGroupVersion.Group, "extensions/status", "patch", "update"),
+		newPermissions(controllerClusterRole, "", "", "namespaces", "get", "list", "watch"),
+		newPermissions(controllerClusterRole, "", "coordination.k8s.io", "leases", "list", "watch"),
+		newPermissions(controllerClusterRole, "", "events.k8s.io", "events", "create", "update", "patch"),
+		newPermissions(controllerClusterRole, "", resourcesv1alpha1.SchemeGroupVersion.Group, "managedresources", "get", "list", "watch", "create", "update", "patch", "delete"),
+		newPermissions(controllerClusterRole, "", "", "secrets", "get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"),
+	)
+
+	for _, ns := range slices.Compact(slices.Sorted(slices.Values(namespaces))) {
+		perms = slices.Concat(
+			perms,
+			newPermissions(controllerRole, ns, "coordination.k8s.io", "leases", "get", "list", "watch", "create", "update", "patch", "delete"),
+			newPermissions(controllerRole, ns, "", "events", "create", "update", "patch"),
+		)
+	}
+
+	return perms
+}
+
+// ControllerMetricsPermissions returns the permissions required by the
+// controller in the seed cluster for authorizing requests to its metrics
+// server.
+func ControllerMetricsPermissions() []Permission {
+	return metricsPermissions(controllerClusterRole)
+}
+
+// ControllerWebhookPermissions returns the permissions required by the
+// controller in the seed cluster for serving the seed-side webhook with the
+// given name, i.e. for registering its webhook configuration.
+func ControllerWebhookPermissions(name string) []Permission {
+	perms := newPermissions(controllerClusterRole, "", "admissionregistration.k8s.io", "validatingwebhookconfigurations", "create", "get", "list", "watch")
+
+	for _, perm := range newPermissions(controllerClusterRole, "", "admissionregistration.k8s.io", "validatingwebhookconfigurations", "patch", "update") {
+		perm.Name = name
+		perms = append(perms, perm)
+	}
+
+	return perms
+}
+
+// NewControllerChecker returns a [Checker] for the requirements of the
+// controller, which manages its leases in the given namespaces.
+func NewControllerChecker(c client.Client, leaseNamespaces ...string) (*Checker, error) {
+	return New(
+		c,
+		WithCRDs(ControllerCRDs()...),
+		WithNamespaces(leaseNamespaces...),
+		WithPermissions(ControllerPermissions(leaseNamespaces...)...),
+	)
+}
+
+// WebhookCRDs returns the kinds of resources, which must be served by the
+// garden cluster for the webhook.
+func WebhookCRDs() []schema.GroupVersionKind {
+	return []schema.GroupVersionKind{
+		gardencorev1beta1.SchemeGroupVersion.WithKind("Shoot"),
+	}
+}
+
+// WebhookPermissions returns the permissions required by the webhook with the
+// given name in the garden cluster.
+func WebhookPermissions(name string) []Permission {
+	perms := slices.Concat(
+		newPermissions(webhookClusterRole, "", gardencorev1beta1.SchemeGroupVersion.Group, "shoots", "get", "list", "watch"),
+		newPermissions(webhookClusterRole, "", "", "namespaces", "get", "list", "watch"),
+		newPermissions(webhookClusterRole, "", "admissionregistration.k8s.io", "validatingwebhookconfigurations", "create", "get", "list", "watch"),
+	)
+
+	for _, perm := range newPermissions(webhookClusterRole, "", "admissionregistration.k8s.io", "validatingwebhookconfigurations", "patch", "update") {
+		perm.Name = name
+		perms = append(perms, perm)
+	}
+
+	return perms
+}
+
+// WebhookRuntimePermissions returns the permissions required by the webhook in
+// the runtime cluster. The given namespace is the namespace of the webhook, in
+// which the certificates of the webhook server and the leases with the given
+// names, i.e. the leader election and the heartbeat lease, are managed.
+func WebhookRuntimePermissions(namespace string, leaseNames ...string) []Permission {
+	perms := slices.Concat(
+		newPermissions(webhookRole, namespace, "", "secrets", "create", "get", "list", "watch", "update", "patch", "delete"),
+		newPermissions(webhookRole, namespace, "coordination.k8s.io", "leases", "create", "get", "list", "watch"),
+		newPermissions(webhookRole, namespace, "", "events", "create", "patch", "update"),
+	)
+
+	for _, name := range leaseNames {
+		for _, perm := range newPermissions(webhookRole, namespace, "coordination.k8s.io", "leases", "patch", "update") {
+			perm.Name = name
+			perms = append(perms, perm)
+		}
+	}
+
+	return perms
+}
+
+// WebhookMetricsPermissions returns the permissions required by the webhook in
+// the runtime cluster for authorizing requests to its metrics server.
+func WebhookMetricsPermissions() []Permission {
+	return metricsPermissions(webhookRuntimeClusterRole)
+}
+
+// metricsPermissions returns the permissions for reviewing the tokens and
+// access of requests to the metrics server, which are granted by the given
+// RBAC template.
+func metricsPermissions(template string) []Permission {
+	return slices.Concat(
+		newPermissions(template, "", "authentication.k8s.io", "tokenreviews", "create"),
+		newPermissions(template, "", "authorization.k8s.io", "subjectaccessreviews", "create"),
+	)
+}
@@ -0,0 +1,500 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package render provides offline rendering of the objects, which the
+// extension actuator deploys for a given input. The actuator is run against an
+// in-memory fake client, which makes it possible to review and golden-file
+// test the produced manifests without a cluster.
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	extensionscontroller "github.com/gardener/gardener/extensions/pkg/controller"
+	v1beta1helper "github.com/gardener/gardener/pkg/api/core/v1beta1/helper"
+	corev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	v1beta1constants "github.com/gardener/gardener/pkg/apis/core/v1beta1/constants"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	resourcesv1alpha1 "github.com/gardener/gardener/pkg/apis/resources/v1alpha1"
+	reconcilerutils "github.com/gardener/gardener/pkg/controllerutils/reconciler"
+	"github.com/gardener/gardener/pkg/resourcemanager/controller/garbagecollector/references"
+	"github.com/gardener/gardener/pkg/utils"
+	"github.com/gardener/gardener/pkg/utils/managedresources"
+	secretsmanager "github.com/gardener/gardener/pkg/utils/secrets/manager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	kjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	exampleactuator "gardener-extension-example/pkg/actuator/example"
+	configinstall "gardener-extension-example/pkg/apis/config/install"
+)
+
+const (
+	// DefaultNamespace is the shoot namespace used for rendering, when the
+	// input does not specify one.
+	DefaultNamespace = "shoot--local--local"
+
+	// maxAttempts is the max number of reconciliations performed while
+	// waiting for the components to roll out.
+	maxAttempts = 10
+)
+
+// ErrNoExtension is an error, which is returned when the input does not
+// contain an extension resource or a shoot, which enables the extension.
+var ErrNoExtension = errors.New("no extension found")
+
+// Input provides the objects, for which the objects deployed by the actuator
+// are rendered.
+type Input struct {
+	// Extension is the extension resource to reconcile. If it is not
+	// specified, it is derived from the Shoot.
+	Extension *extensionsv1alpha1.Extension
+
+	// Cluster is the cluster resource of the shoot. If it is not
+	// specified, it is derived from the Shoot.
+	Cluster *extensionsv1alpha1.Cluster
+
+	// Shoot is the shoot, which enables the extension.
+	Shoot *corev1beta1.Shoot
+
+	// Secrets are the secrets referenced by the resources of the shoot.
+	// They are copied into the shoot namespace the same way gardenlet
+	// does.
+	Secrets []corev1.Secret
+}
+
+// Renderer renders the objects, which the actuator deploys for a given
+// [Input].
+type Renderer struct {
+	scheme *runtime.Scheme
+	logger logr.Logger
+	image  string
+}
+
+// Option is a function, which configures the [Renderer].
+type Option func(r *Renderer) error
+
+// New creates a new [Renderer] with the given options.
+func New(opts ...Option) (*Renderer, error) {
+	r := &Renderer{
+		scheme: runtime.NewScheme(),
+		logger: logr.Discard(),
+		image:  exampleactuator.DefaultImage,
+	}
+
+	utilruntime.Must(clientgoscheme.AddToScheme(r.scheme))
+	utilruntime.Must(extensionscontroller.AddToScheme(r.scheme))
+	utilruntime.Must(resourcesv1alpha1.AddToScheme(r.scheme))
+	utilruntime.Must(corev1beta1.AddToScheme(r.scheme))
+	configinstall.Install(r.scheme)
+
+	for _, opt := range opts {
+		if err := opt(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// WithLogger is an [Option], which configures the [Renderer] to use the given
+// [logr.Logger] for the actuator.
+func WithLogger(logger logr.Logger) Option {
+	opt := func(r *Renderer) error {
+		r.logger = logger
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithImage is an [Option], which configures the [Renderer] to render the
+// seed-side workload with the given image.
+func WithImage(image string) Option {
+	opt := func(r *Renderer) error {
+		r.image = image
+
+		return nil
+	}
+
+	return opt
+}
+
+// LoadFiles loads the [Input] from the YAML documents in the files with the
+// given paths. The special path "-" refers to the standard input.
+func (r *Renderer) LoadFiles(paths ...string) (*Input, error) {
+	in := &Input{}
+	for _, path := range paths {
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(path) //nolint:gosec // reading user-provided manifests is intended
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.Load(in, bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+	}
+
+	return in, nil
+}
+
+// Load adds the objects from the YAML documents read from the given
+// [io.Reader] to the given [Input]. Supported objects are Extension, Cluster,
+// Shoot and Secret resources.
+func (r *Renderer) Load(in *Input, rd io.Reader) error {
+	decoder := serializer.NewCodecFactory(r.scheme, serializer.EnableStrict).UniversalDeserializer()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(rd))
+	for {
+		data, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(data, nil, nil)
+		if err != nil {
+			return err
+		}
+
+		switch o := obj.(type) {
+		case *extensionsv1alpha1.Extension:
+			in.Extension = o
+		case *extensionsv1alpha1.Cluster:
+			in.Cluster = o
+		case *corev1beta1.Shoot:
+			in.Shoot = o
+		case *corev1.Secret:
+			in.Secrets = append(in.Secrets, *o)
+		default:
+			return fmt.Errorf("unsupported object kind %s", obj.GetObjectKind().GroupVersionKind())
+		}
+	}
+}
+
+// Render reconciles the extension resource from the given [Input] against an
+// in-memory fake client and writes the resulting ManagedResources along with
+// their decoded objects as YAML to the given [io.Writer].
+//
+// The names of secrets generated by the secrets manager and the checksums
+// annotated for rolling out their rotation depend on randomly generated data,
+// so they are replaced by the stable names and the checksums thereof in order
+// to produce reproducible output.
+func (r *Renderer) Render(ctx context.Context, in *Input, w io.Writer) error {
+	ex, cluster, err := r.complete(in)
+	if err != nil {
+		return err
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ex.Namespace,
+		},
+	}
+	objs := []client.Object{namespace, cluster, ex}
+	for _, secret := range in.Secrets {
+		ref := secret.DeepCopy()
+		ref.ObjectMeta = metav1.ObjectMeta{
+			Name:      v1beta1constants.ReferencedResourcesPrefix + secret.Name,
+			Namespace: ex.Namespace,
+			Labels:    secret.Labels,
+		}
+		objs = append(objs, ref)
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(r.scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&extensionsv1alpha1.Extension{}, &resourcesv1alpha1.ManagedResource{}).
+		Build()
+
+	if err := r.reconcile(ctx, c, ex); err != nil {
+		return err
+	}
+
+	return r.write(ctx, c, ex.Namespace, w)
+}
+
+// complete returns the extension and cluster resources for the given
+// [Input], deriving them from the shoot, if they were not specified.
+func (r *Renderer) complete(in *Input) (*extensionsv1alpha1.Extension, *extensionsv1alpha1.Cluster, error) {
+	ex := in.Extension
+	if ex == nil {
+		if in.Shoot == nil {
+			return nil, nil, ErrNoExtension
+		}
+
+		idx := slices.IndexFunc(in.Shoot.Spec.Extensions, func(ext corev1beta1.Extension) bool {
+			return ext.Type == exampleactuator.ExtensionType
+		})
+		if idx == -1 {
+			return nil, nil, fmt.Errorf("%w: shoot does not enable extension %s", ErrNoExtension, exampleactuator.ExtensionType)
+		}
+
+		ex = &extensionsv1alpha1.Extension{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      exampleactuator.ExtensionType,
+				Namespace: technicalID(in.Shoot),
+			},
+			Spec: extensionsv1alpha1.ExtensionSpec{
+				DefaultSpec: extensionsv1alpha1.DefaultSpec{
+					Type:           exampleactuator.ExtensionType,
+					ProviderConfig: in.Shoot.Spec.Extensions[idx].ProviderConfig,
+				},
+			},
+		}
+	}
+
+	ex = ex.DeepCopy()
+	ex.ResourceVersion = ""
+	if ex.Namespace == "" {
+		ex.Namespace = DefaultNamespace
+	}
+
+	if in.Cluster != nil {
+		cluster := in.Cluster.DeepCopy()
+		cluster.ResourceVersion = ""
+		cluster.Name = ex.Namespace
+
+		return ex, cluster, nil
+	}
+
+	shoot := in.Shoot
+	if shoot == nil {
+		// Derive a minimal shoot from the shoot namespace, which has
+		// the form shoot--<project>--<name>.
+		parts := strings.SplitN(strings.TrimPrefix(ex.Namespace, v1beta1constants.TechnicalIDPrefix+"-"), "--", 2)
+		shoot = &corev1beta1.Shoot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      parts[len(parts)-1],
+				Namespace: "garden-" + parts[0],
+			},
+		}
+	}
+
+	shootData, err := json.Marshal(shoot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to serialize shoot: %w", err)
+	}
+
+	cluster := &extensionsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ex.Namespace,
+		},
+		Spec: extensionsv1alpha1.ClusterSpec{
+			CloudProfile: runtime.RawExtension{Raw: []byte("{}")},
+			Seed:         runtime.RawExtension{Raw: []byte("{}")},
+			Shoot:        runtime.RawExtension{Raw: shootData},
+		},
+	}
+
+	return ex, cluster, nil
+}
+
+// reconcile runs the actuator for the given extension resource. Since there is
+// no gardener-resource-manager, the ManagedResources are marked as healthy
+// whenever the actuator waits for the components to roll out.
+func (r *Renderer) reconcile(ctx context.Context, c client.Client, obj *extensionsv1alpha1.Extension) error {
+	act, err := exampleactuator.New(c, exampleactuator.WithImage(r.image))
+	if err != nil {
+		return err
+	}
+
+	ex := &extensionsv1alpha1.Extension{}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), ex); err != nil {
+		return err
+	}
+
+	for range maxAttempts {
+		// Restore the extension, so that any persisted state of the
+		// given extension resource is re-used.
+		err := act.Restore(ctx, r.logger, ex)
+		if err == nil {
+			return nil
+		}
+
+		// Only errors caused by components, which have not been
+		// rolled out yet, are retried. Coded errors, e.g. configuration
+		// problems or missing dependencies, are not fixed by retrying.
+		var requeueErr *reconcilerutils.RequeueAfterError
+		if !errors.As(err, &requeueErr) || len(v1beta1helper.ExtractErrorCodes(requeueErr.Cause)) > 0 {
+			return fmt.Errorf("failed to reconcile extension: %w", reconcilerutils.ReconcileErrCauseOrErr(err))
+		}
+
+		if err := markHealthy(ctx, c, ex.Namespace); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("components have not been rolled out after %d attempts", maxAttempts)
+}
+
+// write writes the ManagedResources from the given namespace along with their
+// decoded objects as YAML to the given [io.Writer].
+func (r *Renderer) write(ctx context.Context, c client.Client, namespace string, w io.Writer) error {
+	var items resourcesv1alpha1.ManagedResourceList
+	if err := c.List(ctx, &items, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list managed resources: %w", err)
+	}
+	slices.SortFunc(items.Items, func(a, b resourcesv1alpha1.ManagedResource) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	encoder := kjson.NewSerializerWithOptions(kjson.DefaultMetaFactory, r.scheme, r.scheme, kjson.SerializerOptions{Yaml: true})
+	var buf bytes.Buffer
+	for _, mr := range items.Items {
+		objects, err := managedresources.GetObjects(ctx, c, namespace, mr.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get objects of managed resource %s: %w", mr.Name, err)
+		}
+		slices.SortFunc(objects, func(a, b client.Object) int {
+			return cmp.Or(
+				cmp.Compare(a.GetObjectKind().GroupVersionKind().Kind, b.GetObjectKind().GroupVersionKind().Kind),
+				cmp.Compare(a.GetName(), b.GetName()),
+			)
+		})
+
+		// The data secrets and the annotations referencing them are
+		// replaced by the decoded objects.
+		rendered := &resourcesv1alpha1.ManagedResource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      mr.Name,
+				Namespace: mr.Namespace,
+				Labels:    mr.Labels,
+			},
+			Spec: mr.Spec,
+		}
+		rendered.Spec.SecretRefs = nil
+		for key, value := range mr.Annotations {
+			if !strings.HasPrefix(key, references.AnnotationKeyPrefix) {
+				metav1.SetMetaDataAnnotation(&rendered.ObjectMeta, key, value)
+			}
+		}
+
+		for _, obj := range append([]client.Object{rendered}, objects...) {
+			gvk, err := apiutil.GVKForObject(obj, r.scheme)
+			if err != nil {
+				return err
+			}
+			obj.GetObjectKind().SetGroupVersionKind(gvk)
+
+			fmt.Fprintf(&buf, "---\n# Source: %s\n", mr.Name)
+			if err := encoder.Encode(obj, &buf); err != nil {
+				return fmt.Errorf("failed to encode %s %s: %w", gvk.Kind, obj.GetName(), err)
+			}
+		}
+	}
+
+	replacer, err := secretNameReplacer(ctx, c, namespace)
+	if err != nil {
+		return err
+	}
+
+	_, err = replacer.WriteString(w, buf.String())
+
+	return err
+}
+
+// secretNameReplacer returns a [strings.Replacer], which replaces the names and
+// the checksums of the secrets generated by the secrets manager of the
+// actuator with their stable names and the checksums thereof.
+func secretNameReplacer(ctx context.Context, c client.Client, namespace string) (*strings.Replacer, error) {
+	labels := client.MatchingLabels{
+		secretsmanager.LabelKeyManagedBy:       secretsmanager.LabelValueSecretsManager,
+		secretsmanager.LabelKeyManagerIdentity: exampleactuator.SecretsManagerIdentity,
+	}
+
+	var items corev1.SecretList
+	if err := c.List(ctx, &items, client.InNamespace(namespace), labels); err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+
+	oldnew := make([]string, 0, 4*len(items.Items))
+	for _, secret := range items.Items {
+		if name := secret.Labels[secretsmanager.LabelKeyName]; name != "" && name != secret.Name {
+			oldnew = append(
+				oldnew,
+				secret.Name, name,
+				utils.ComputeSecretChecksum(secret.Data), utils.ComputeSHA256Hex([]byte(name)),
+			)
+		}
+	}
+
+	return strings.NewReplacer(oldnew...), nil
+}
+
+// markHealthy marks all ManagedResources in the given namespace as applied and
+// healthy.
+func markHealthy(ctx context.Context, c client.Client, namespace string) error {
+	var items resourcesv1alpha1.ManagedResourceList
+	if err := c.List(ctx, &items, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("failed to list managed resources: %w", err)
+	}
+
+	for _, mr := range items.Items {
+		patch := client.MergeFrom(mr.DeepCopy())
+		mr.Status.ObservedGeneration = mr.Generation
+		mr.Status.Conditions = []corev1beta1.Condition{
+			{
+				Type:   resourcesv1alpha1.ResourcesApplied,
+				Status: corev1beta1.ConditionTrue,
+			},
+			{
+				Type:   resourcesv1alpha1.ResourcesHealthy,
+				Status: corev1beta1.ConditionTrue,
+			},
+		}
+		if err := c.Status().Patch(ctx, &mr, patch); err != nil {
+			return fmt.Errorf("failed to update status of managed resource %s: %w", mr.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// technicalID returns the name of the shoot namespace in the seed cluster for
+// the given shoot.
+func technicalID(shoot *corev1beta1.Shoot) string {
+	if shoot.Status.TechnicalID != "" {
+		return shoot.Status.TechnicalID
+	}
+
+	project := strings.TrimPrefix(shoot.Namespace, "garden-")
+	if shoot.Namespace == v1beta1constants.GardenNamespace || project == "" {
+		project = v1beta1constants.GardenNamespace
+	}
+
+	return fmt.Sprintf("%s-%s--%s", v1beta1constants.TechnicalIDPrefix, project, shoot.Name)
+}
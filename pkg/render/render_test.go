@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package render_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"gardener-extension-example/pkg/render"
+)
+
+const extensionManifests = `apiVersion: extensions.gardener.cloud/v1alpha1
+kind: Extension
+metadata:
+  name: example
+  namespace: shoot--dev--foo
+spec:
+  type: example
+  providerConfig:
+    apiVersion: example.extensions.gardener.cloud/v1alpha1
+    kind: ExampleConfig
+    spec:
+      foo: baz
+      secretRefs:
+        - name: api-token
+---
+apiVersion: core.gardener.cloud/v1beta1
+kind: Shoot
+metadata:
+  name: foo
+  namespace: garden-dev
+spec:
+  resources:
+    - name: api-token
+      resourceRef:
+        apiVersion: v1
+        kind: Secret
+        name: my-api-token
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-api-token
+  namespace: garden-dev
+data:
+  token: Zm9v
+`
+
+var _ = Describe("Render", func() {
+	var (
+		ctx      = context.Background()
+		renderer *render.Renderer
+	)
+
+	BeforeEach(func() {
+		var err error
+		renderer, err = render.New(render.WithImage("example:latest"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should render the example shoot reproducibly", func() {
+		in, err := renderer.LoadFiles("../../examples/shoot.yaml")
+		Expect(err).NotTo(HaveOccurred())
+
+		defaultRenderer, err := render.New()
+		Expect(err).NotTo(HaveOccurred())
+
+		var first, second bytes.Buffer
+		Expect(defaultRenderer.Render(ctx, in, &first)).To(Succeed())
+		Expect(defaultRenderer.Render(ctx, in, &second)).To(Succeed())
+		Expect(first.String()).To(Equal(second.String()))
+
+		golden, err := os.ReadFile("testdata/shoot.golden.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.String()).To(Equal(string(golden)))
+	})
+
+	It("should render an extension resource with referenced secrets", func() {
+		in := &render.Input{}
+		Expect(renderer.Load(in, strings.NewReader(extensionManifests))).To(Succeed())
+		Expect(in.Extension).NotTo(BeNil())
+		Expect(in.Secrets).To(HaveLen(1))
+
+		var out bytes.Buffer
+		Expect(renderer.Render(ctx, in, &out)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("namespace: shoot--dev--foo"))
+		Expect(out.String()).To(ContainSubstring("foo: baz"))
+		Expect(out.String()).To(ContainSubstring("image: example:latest"))
+		Expect(out.String()).To(ContainSubstring("secretName: ref-my-api-token"))
+	})
+
+	It("should fail without extension", func() {
+		err := renderer.Render(ctx, &render.Input{}, &bytes.Buffer{})
+		Expect(err).To(MatchError(render.ErrNoExtension))
+	})
+
+	It("should fail on unsupported objects", func() {
+		manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+`
+		err := renderer.Load(&render.Input{}, strings.NewReader(manifest))
+		Expect(err).To(MatchError(ContainSubstring("unsupported object kind")))
+	})
+})
@@ -0,0 +1,607 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sharding provides a lease-based sharder, which distributes the
+// reconciliation of shoot namespaces across the replicas of the controller.
+package sharding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"gardener-extension-example/pkg/metrics"
+)
+
+const (
+	// DefaultLeaseDuration is the default duration after which a replica,
+	// which did not renew its lease, is considered gone.
+	DefaultLeaseDuration = 40 * time.Second
+
+	// DefaultRenewInterval is the default interval on which a replica
+	// renews its lease and rebalances the shards.
+	DefaultRenewInterval = 10 * time.Second
+
+	// LabelKeyShardGroup is the key of the label, which groups the leases
+	// of the replicas sharing the reconciliation of the shoot namespaces.
+	LabelKeyShardGroup = "example.extensions.gardener.cloud/shard-group"
+
+	// AnnotationKeyMembers is the key of the annotation of a lease, which
+	// lists the members of the group, from which the replica derives the
+	// namespaces it claims.
+	AnnotationKeyMembers = "example.extensions.gardener.cloud/shard-members"
+
+	// releaseTimeout is the timeout for releasing the lease of the replica
+	// on shutdown.
+	releaseTimeout = 10 * time.Second
+
+	// eventsBufferSize is the number of events for extension resources,
+	// which may be buffered during rebalancing.
+	eventsBufferSize = 1024
+)
+
+// ErrInvalidSharder is an error, which is returned when creating a [Sharder]
+// with invalid config settings.
+var ErrInvalidSharder = errors.New("invalid sharder")
+
+// Sharder is a [manager.Runnable], which coordinates the replicas of the
+// controller via leases. Each replica holds a lease of its own, which it
+// renews periodically. The shoot namespaces are assigned to the replicas with
+// a live lease via rendezvous hashing, so that only the namespaces of a
+// replica, which comes or goes, move to other replicas.
+//
+// Each replica publishes the members, from which it derives the namespaces it
+// claims, in its lease. A replica takes over a namespace only, once its
+// previous owner published members, which assign the namespace to another
+// replica, or once the lease of the previous owner expired or was released.
+// The previous owner publishes its new members only after the reconciliations
+// in the namespaces, which it hands over, have completed, see
+// [Sharder.Acquire].
+//
+// [manager.Runnable]: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+type Sharder struct {
+	client        client.Client
+	reader        client.Reader
+	group         string
+	namespace     string
+	identity      string
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	clock         clock.Clock
+	logger        logr.Logger
+
+	// members are the sorted identities of the replicas with a live lease.
+	members []string
+
+	// published are the members, which have been published in the lease of
+	// the replica, when it has been renewed at renewedAt.
+	published []string
+	renewedAt time.Time
+
+	// claims are the members published by the other replicas with a live
+	// lease, keyed by their identities.
+	claims map[string][]string
+
+	// owned are the namespaces with extension resources, which have been
+	// owned by the replica during the last sync.
+	owned sets.Set[string]
+
+	// active counts the reconciliations in progress per namespace.
+	active map[string]int
+
+	mu sync.RWMutex
+
+	// events receives the extension resources in namespaces, which the
+	// replica owns after rebalancing, when replaying is enabled.
+	events chan event.GenericEvent
+	replay bool
+}
+
+// Option is a function, which configures the [Sharder].
+type Option func(s *Sharder) error
+
+// New creates a new [Sharder], which uses the given [client.Client] to manage
+// its lease and to list the extension resources.
+func New(c client.Client, opts ...Option) (*Sharder, error) {
+	if c == nil {
+		return nil, fmt.Errorf("%w: no client specified", ErrInvalidSharder)
+	}
+
+	s := &Sharder{
+		client:        c,
+		reader:        c,
+		leaseDuration: DefaultLeaseDuration,
+		renewInterval: DefaultRenewInterval,
+		clock:         clock.RealClock{},
+		logger:        ctrllog.Log.WithName("sharder"),
+		members:       make([]string, 0),
+		published:     make([]string, 0),
+		claims:        make(map[string][]string),
+		owned:         sets.New[string](),
+		active:        make(map[string]int),
+		events:        make(chan event.GenericEvent, eventsBufferSize),
+	}
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.group == "" {
+		return nil, fmt.Errorf("%w: missing shard group", ErrInvalidSharder)
+	}
+	if s.namespace == "" {
+		return nil, fmt.Errorf("%w: missing lease namespace", ErrInvalidSharder)
+	}
+	if s.identity == "" {
+		return nil, fmt.Errorf("%w: missing identity", ErrInvalidSharder)
+	}
+	if s.renewInterval <= 0 || s.renewInterval >= s.leaseDuration {
+		return nil, fmt.Errorf("%w: renew interval must be positive and shorter than the lease duration", ErrInvalidSharder)
+	}
+
+	return s, nil
+}
+
+// WithGroup is an [Option], which configures the [Sharder] to share the shoot
+// namespaces with the replicas of the given group, e.g. the name of the
+// extension.
+func WithGroup(group string) Option {
+	opt := func(s *Sharder) error {
+		s.group = group
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithLeaseNamespace is an [Option], which configures the [Sharder] to manage
+// the leases in the given namespace.
+func WithLeaseNamespace(namespace string) Option {
+	opt := func(s *Sharder) error {
+		s.namespace = namespace
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithIdentity is an [Option], which configures the [Sharder] with the given
+// identity of the replica, e.g. the name of its pod.
+func WithIdentity(identity string) Option {
+	opt := func(s *Sharder) error {
+		s.identity = identity
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithLeaseDuration is an [Option], which configures the [Sharder] to
+// consider replicas gone, which did not renew their lease within the given
+// duration.
+func WithLeaseDuration(duration time.Duration) Option {
+	opt := func(s *Sharder) error {
+		s.leaseDuration = duration
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithRenewInterval is an [Option], which configures the [Sharder] to renew
+// its lease and to rebalance the shards on the given interval.
+func WithRenewInterval(interval time.Duration) Option {
+	opt := func(s *Sharder) error {
+		s.renewInterval = interval
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithReader is an [Option], which configures the [Sharder] to read the leases
+// with the given [client.Reader], e.g. the API reader of the manager, so that
+// no informer is started for leases.
+func WithReader(r client.Reader) Option {
+	opt := func(s *Sharder) error {
+		s.reader = r
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithClock is an [Option], which configures the [Sharder] to use the given
+// [clock.Clock].
+func WithClock(clk clock.Clock) Option {
+	opt := func(s *Sharder) error {
+		s.clock = clk
+
+		return nil
+	}
+
+	return opt
+}
+
+// WithLogger is an [Option], which configures the [Sharder] to use the given
+// [logr.Logger].
+func WithLogger(logger logr.Logger) Option {
+	opt := func(s *Sharder) error {
+		s.logger = logger
+
+		return nil
+	}
+
+	return opt
+}
+
+// Start starts the [Sharder] and blocks until the given context is cancelled.
+// The lease of the replica is released on shutdown, so that the other
+// replicas take over its namespaces without waiting for the lease to expire.
+// This method implements the [manager.Runnable] interface.
+//
+// [manager.Runnable]: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#Runnable
+func (s *Sharder) Start(ctx context.Context) error {
+	s.logger.Info("starting sharder", "group", s.group, "identity", s.identity, "leaseDuration", s.leaseDuration)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.Sync(ctx); err != nil {
+			s.logger.Error(err, "failed to sync shards")
+		}
+	}, s.renewInterval)
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	return s.Release(releaseCtx)
+}
+
+// NeedLeaderElection returns false, since all replicas take part in the
+// sharding. This method implements the [manager.LeaderElectionRunnable]
+// interface.
+//
+// [manager.LeaderElectionRunnable]: https://pkg.go.dev/sigs.k8s.io/controller-runtime/pkg/manager#LeaderElectionRunnable
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Sync renews the lease of the replica and rebalances the shards according to
+// the replicas with a live lease. When replaying is enabled, the extension
+// resources in the namespaces newly owned by the replica are sent to the
+// channel returned by [Sharder.Events].
+func (s *Sharder) Sync(ctx context.Context) error {
+	members, claims, err := s.liveMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	changed := !slices.Equal(s.members, members)
+	s.members = members
+	s.claims = claims
+
+	// The namespaces, which move to other replicas, are handed over by
+	// publishing the new members, once their reconciliations completed.
+	publish := s.published
+	if s.handedOver(members) {
+		publish = members
+	}
+	replay := s.replay
+	previous := s.owned
+	s.mu.Unlock()
+
+	metrics.ShardingMembers.Set(float64(len(members)))
+	if changed {
+		s.logger.Info("rebalancing shards", "members", members)
+		metrics.ShardingRebalancesTotal.Inc()
+	}
+
+	if err := s.renew(ctx, publish); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.published = publish
+	s.renewedAt = s.clock.Now()
+	s.mu.Unlock()
+
+	var items extensionsv1alpha1.ExtensionList
+	if err := s.client.List(ctx, &items); err != nil {
+		return fmt.Errorf("failed to list extensions: %w", err)
+	}
+
+	owned := sets.New[string]()
+	for _, ex := range items.Items {
+		if !s.Owns(ex.Namespace) {
+			continue
+		}
+		owned.Insert(ex.Namespace)
+
+		if !replay || previous.Has(ex.Namespace) {
+			continue
+		}
+
+		select {
+		case s.events <- event.GenericEvent{Object: ex.DeepCopy()}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	s.owned = owned
+	s.mu.Unlock()
+
+	metrics.ShardingOwnedNamespaces.Set(float64(owned.Len()))
+
+	return nil
+}
+
+// Release deletes the lease of the replica and gives up all namespaces.
+func (s *Sharder) Release(ctx context.Context) error {
+	s.mu.Lock()
+	s.members = make([]string, 0)
+	s.published = make([]string, 0)
+	s.owned = sets.New[string]()
+	s.mu.Unlock()
+
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      s.leaseName(),
+			Namespace: s.namespace,
+		},
+	}
+	if err := s.client.Delete(ctx, lease); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to release lease %s: %w", client.ObjectKeyFromObject(lease), err)
+	}
+
+	s.logger.Info("released lease", "lease", client.ObjectKeyFromObject(lease))
+
+	return nil
+}
+
+// Owns returns true, if the given shoot namespace belongs to the shard of the
+// replica. No namespace is owned before the first sync, after the lease has
+// been released, and while the lease could not be renewed.
+func (s *Sharder) Owns(namespace string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.owns(namespace)
+}
+
+// Acquire returns true, if the given shoot namespace belongs to the shard of
+// the replica, and marks a reconciliation in the namespace as in progress.
+// The namespace is not handed over to another replica, until the
+// reconciliation is marked as completed via [Sharder.Done].
+func (s *Sharder) Acquire(namespace string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.owns(namespace) {
+		return false
+	}
+	s.active[namespace]++
+
+	return true
+}
+
+// Done marks a reconciliation in the given shoot namespace, which has been
+// acquired via [Sharder.Acquire], as completed.
+func (s *Sharder) Done(namespace string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.active[namespace]--
+	if s.active[namespace] <= 0 {
+		delete(s.active, namespace)
+	}
+}
+
+// owns returns true, if the given shoot namespace belongs to the shard of the
+// replica according to both, its current and its published members, and no
+// other replica claims it. The caller must hold the lock.
+func (s *Sharder) owns(namespace string) bool {
+	if s.renewedAt.IsZero() || !s.clock.Now().Before(s.renewedAt.Add(s.leaseDuration)) {
+		return false
+	}
+
+	if owner(s.members, namespace) != s.identity || owner(s.published, namespace) != s.identity {
+		return false
+	}
+
+	for member, view := range s.claims {
+		if owner(view, namespace) == member {
+			return false
+		}
+	}
+
+	return true
+}
+
+// handedOver returns true, if none of the namespaces with reconciliations in
+// progress moves to another replica with the given members. The caller must
+// hold the lock.
+func (s *Sharder) handedOver(members []string) bool {
+	for namespace := range s.active {
+		if owner(members, namespace) != s.identity {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Predicate returns a [predicate.Predicate], which matches the objects in
+// shoot namespaces owned by the replica.
+func (s *Sharder) Predicate() predicate.Predicate {
+	return predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return s.Owns(obj.GetNamespace())
+	})
+}
+
+// Events enables replaying and returns a channel, which receives the
+// extension resources in namespaces, which the replica owns after
+// rebalancing. Events for these resources have been dropped while they were
+// owned by another replica, so they must be reconciled by the new owner.
+func (s *Sharder) Events() <-chan event.GenericEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replay = true
+
+	return s.events
+}
+
+// renew creates or renews the lease of the replica, which publishes the given
+// members.
+func (s *Sharder) renew(ctx context.Context, members []string) error {
+	now := metav1.NewMicroTime(s.clock.Now())
+	lease := &coordinationv1.Lease{}
+	key := client.ObjectKey{Namespace: s.namespace, Name: s.leaseName()}
+
+	if err := s.reader.Get(ctx, key, lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get lease %s: %w", key, err)
+		}
+
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        key.Name,
+				Namespace:   key.Namespace,
+				Labels:      map[string]string{LabelKeyShardGroup: s.group},
+				Annotations: map[string]string{AnnotationKeyMembers: strings.Join(members, ",")},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &s.identity,
+				LeaseDurationSeconds: new(int32(s.leaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := s.client.Create(ctx, lease); err != nil {
+			return fmt.Errorf("failed to create lease %s: %w", key, err)
+		}
+
+		return nil
+	}
+
+	patch := client.MergeFrom(lease.DeepCopy())
+	metav1.SetMetaDataLabel(&lease.ObjectMeta, LabelKeyShardGroup, s.group)
+	metav1.SetMetaDataAnnotation(&lease.ObjectMeta, AnnotationKeyMembers, strings.Join(members, ","))
+	lease.Spec.HolderIdentity = &s.identity
+	lease.Spec.LeaseDurationSeconds = new(int32(s.leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	if err := s.client.Patch(ctx, lease, patch); err != nil {
+		return fmt.Errorf("failed to renew lease %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// liveMembers returns the sorted identities of the replicas of the group,
+// whose leases have not expired, and the members published by the other
+// replicas, keyed by their identities. Leases without published members, e.g.
+// of replicas running an older version, are assumed to publish the live
+// members.
+func (s *Sharder) liveMembers(ctx context.Context) ([]string, map[string][]string, error) {
+	var leases coordinationv1.LeaseList
+	err := s.reader.List(
+		ctx,
+		&leases,
+		client.InNamespace(s.namespace),
+		client.MatchingLabels{LabelKeyShardGroup: s.group},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list leases: %w", err)
+	}
+
+	now := s.clock.Now()
+	members := sets.New(s.identity)
+	published := make(map[string]*string)
+	for _, lease := range leases.Items {
+		spec := lease.Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+
+		expiry := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if !now.Before(expiry) || *spec.HolderIdentity == s.identity {
+			continue
+		}
+
+		members.Insert(*spec.HolderIdentity)
+		value, ok := lease.Annotations[AnnotationKeyMembers]
+		if ok {
+			published[*spec.HolderIdentity] = &value
+		} else {
+			published[*spec.HolderIdentity] = nil
+		}
+	}
+
+	result := sets.List(members)
+	claims := make(map[string][]string, len(published))
+	for member, value := range published {
+		switch {
+		case value == nil:
+			claims[member] = result
+		case *value == "":
+			claims[member] = make([]string, 0)
+		default:
+			claims[member] = strings.Split(*value, ",")
+		}
+	}
+
+	return result, claims, nil
+}
+
+// leaseName returns the name of the lease of the replica.
+func (s *Sharder) leaseName() string {
+	return s.group + "-" + s.identity
+}
+
+// owner returns the member, which owns the given namespace, i.e. the member
+// with the highest hash of its identity and the namespace. An empty string is
+// returned, if there are no members.
+func owner(members []string, namespace string) string {
+	var (
+		result string
+		score  uint64
+	)
+
+	for _, member := range members {
+		sum := sha256.Sum256([]byte(member + "/" + namespace))
+		if value := binary.BigEndian.Uint64(sum[:8]); result == "" || value > score {
+			result, score = member, value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/client/kubernetes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"gardener-extension-example/pkg/sharding"
+)
+
+var _ = Describe("Sharder", func() {
+	const (
+		group          = "gardener-extension-example"
+		leaseNamespace = "extension-example"
+	)
+
+	var (
+		ctx        = context.Background()
+		fakeClock  *testclock.FakeClock
+		c          client.Client
+		namespaces []string
+	)
+
+	// newSharder returns a new sharder with the given identity.
+	newSharder := func(identity string) *sharding.Sharder {
+		s, err := sharding.New(
+			c,
+			sharding.WithGroup(group),
+			sharding.WithLeaseNamespace(leaseNamespace),
+			sharding.WithIdentity(identity),
+			sharding.WithClock(fakeClock),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		return s
+	}
+
+	// owners returns the number of the given sharders owning each namespace.
+	owners := func(sharders ...*sharding.Sharder) map[string]int {
+		result := make(map[string]int)
+		for _, ns := range namespaces {
+			result[ns] = 0
+			for _, s := range sharders {
+				if s.Owns(ns) {
+					result[ns]++
+				}
+			}
+		}
+
+		return result
+	}
+
+	// replayed returns the namespaces of the events replayed by the given
+	// sharder.
+	replayed := func(events <-chan event.GenericEvent) []string {
+		result := make([]string, 0)
+		for {
+			select {
+			case e := <-events:
+				result = append(result, e.Object.GetNamespace())
+			default:
+				return result
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		fakeClock = testclock.NewFakeClock(time.Now())
+		objs := make([]client.Object, 0)
+		namespaces = make([]string, 0)
+		for i := range 20 {
+			ns := fmt.Sprintf("shoot--local--shoot%d", i)
+			namespaces = append(namespaces, ns)
+			ex := &extensionsv1alpha1.Extension{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "example",
+					Namespace: ns,
+				},
+			}
+			objs = append(objs, ex)
+		}
+		c = fake.NewClientBuilder().WithScheme(kubernetes.SeedScheme).WithObjects(objs...).Build()
+	})
+
+	It("should fail to create a sharder without identity", func() {
+		_, err := sharding.New(c, sharding.WithGroup(group), sharding.WithLeaseNamespace(leaseNamespace))
+		Expect(err).To(MatchError(sharding.ErrInvalidSharder))
+	})
+
+	It("should fail to create a sharder with a renew interval exceeding the lease duration", func() {
+		_, err := sharding.New(
+			c,
+			sharding.WithGroup(group),
+			sharding.WithLeaseNamespace(leaseNamespace),
+			sharding.WithIdentity("replica-a"),
+			sharding.WithLeaseDuration(time.Second),
+			sharding.WithRenewInterval(time.Minute),
+		)
+		Expect(err).To(MatchError(sharding.ErrInvalidSharder))
+	})
+
+	It("should not own any namespace before the first sync", func() {
+		a := newSharder("replica-a")
+		Expect(owners(a)).To(HaveEach(0))
+	})
+
+	It("should own all namespaces as single replica", func() {
+		a := newSharder("replica-a")
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(owners(a)).To(HaveEach(1))
+
+		lease := &coordinationv1.Lease{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: leaseNamespace, Name: group + "-replica-a"}, lease)).To(Succeed())
+		Expect(lease.Labels).To(HaveKeyWithValue(sharding.LabelKeyShardGroup, group))
+		Expect(lease.Spec.HolderIdentity).To(Equal(new("replica-a")))
+	})
+
+	It("should distribute the namespaces across the replicas", func() {
+		a, b := newSharder("replica-a"), newSharder("replica-b")
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(b.Sync(ctx)).To(Succeed())
+		Expect(owners(a, b)).To(HaveEach(1))
+		Expect(owners(b)).To(HaveEach(0))
+
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(owners(a, b)).To(HaveEach(BeNumerically("<=", 1)))
+		Expect(b.Sync(ctx)).To(Succeed())
+
+		Expect(owners(a, b)).To(HaveEach(1))
+		ownedByA := 0
+		for _, ns := range namespaces {
+			if a.Owns(ns) {
+				ownedByA++
+			}
+		}
+		Expect(ownedByA).To(BeNumerically(">", 0))
+		Expect(ownedByA).To(BeNumerically("<", len(namespaces)))
+	})
+
+	It("should hand over namespaces only after their reconciliations completed", func() {
+		a, b := newSharder("replica-a"), newSharder("replica-b")
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(b.Sync(ctx)).To(Succeed())
+
+		// Find a namespace, which moves to the joining replica
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(b.Sync(ctx)).To(Succeed())
+		var moved string
+		for _, ns := range namespaces {
+			if b.Owns(ns) {
+				moved = ns
+
+				break
+			}
+		}
+		Expect(moved).NotTo(BeEmpty())
+		Expect(b.Release(ctx)).To(Succeed())
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(a.Owns(moved)).To(BeTrue())
+
+		// The namespace is not handed over while it is reconciled
+		b = newSharder("replica-b")
+		Expect(a.Acquire(moved)).To(BeTrue())
+		Expect(b.Sync(ctx)).To(Succeed())
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(b.Sync(ctx)).To(Succeed())
+		Expect(a.Owns(moved)).To(BeFalse())
+		Expect(a.Acquire(moved)).To(BeFalse())
+		Expect(b.Owns(moved)).To(BeFalse())
+		Expect(owners(a, b)).To(HaveEach(BeNumerically("<=", 1)))
+
+		a.Done(moved)
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(b.Sync(ctx)).To(Succeed())
+		Expect(b.Owns(moved)).To(BeTrue())
+		Expect(owners(a, b)).To(HaveEach(1))
+	})
+
+	It("should not own any namespace when the lease could not be renewed", func() {
+		a := newSharder("replica-a")
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(owners(a)).To(HaveEach(1))
+
+		fakeClock.Step(sharding.DefaultLeaseDuration)
+		Expect(owners(a)).To(HaveEach(0))
+	})
+
+	It("should take over the namespaces of released replicas", func() {
+		a, b := newSharder("replica-a"), newSharder("replica-b")
+		eventsA := a.Events()
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(replayed(eventsA)).To(ConsistOf(namespaces))
+		Expect(b.Sync(ctx)).To(Succeed())
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(replayed(eventsA)).To(BeEmpty())
+
+		Expect(b.Release(ctx)).To(Succeed())
+		Expect(owners(b)).To(HaveEach(0))
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(owners(a)).To(HaveEach(1))
+
+		// Only the namespaces taken over from the released replica are
+		// replayed
+		taken := replayed(eventsA)
+		Expect(taken).NotTo(BeEmpty())
+		Expect(len(taken)).To(BeNumerically("<", len(namespaces)))
+	})
+
+	It("should take over the namespaces of replicas with expired leases", func() {
+		a, b := newSharder("replica-a"), newSharder("replica-b")
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(b.Sync(ctx)).To(Succeed())
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(owners(a)).NotTo(HaveEach(1))
+
+		fakeClock.Step(sharding.DefaultLeaseDuration)
+		Expect(a.Sync(ctx)).To(Succeed())
+		Expect(owners(a)).To(HaveEach(1))
+	})
+})